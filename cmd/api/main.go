@@ -8,8 +8,10 @@ import (
 	"github.com/joho/godotenv"
 	"github.com/victtorkaiser/server-apis/internal/config"
 	"github.com/victtorkaiser/server-apis/internal/database"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
 	"github.com/victtorkaiser/server-apis/internal/queue"
 	"github.com/victtorkaiser/server-apis/internal/router"
+	"github.com/victtorkaiser/server-apis/internal/services"
 	"github.com/victtorkaiser/server-apis/internal/workers"
 )
 
@@ -36,33 +38,42 @@ func main() {
 	// Conecta ao Redis
 	redisClient := database.ConnectRedis(cfg.RedisURL, cfg.RedisPassword, cfg.RedisDB)
 
-	// Conecta ao RabbitMQ (opcional)
-	var rabbitMQ *queue.RabbitMQ
-	if cfg.RabbitMQURL != "" {
-		rabbitMQ, err = queue.Connect(cfg.RabbitMQURL)
-		if err != nil {
-			log.Printf("⚠️ RabbitMQ não conectado: %v (continuando sem filas)", err)
-		} else {
-			log.Println("✅ RabbitMQ conectado")
-			defer rabbitMQ.Close()
-
-			// Inicia consumers
-			utmifyConsumer := workers.NewUtmifyConsumer(db, rabbitMQ, cfg)
-			if err := utmifyConsumer.Start(); err != nil {
-				log.Printf("⚠️ Erro ao iniciar UtmifyConsumer: %v", err)
-			}
-		}
-	} else {
-		log.Println("ℹ️ RabbitMQ desabilitado (RABBITMQ_URL vazio)")
+	// Barramento de eventos: RabbitMQ, Redis Streams ou Postgres (EVENT_BUS)
+	bus, err := queue.Open(cfg, db, redisClient)
+	if err != nil {
+		log.Fatalf("Erro ao abrir barramento de eventos: %v", err)
+	}
+	log.Printf("✅ Barramento de eventos: %s", bus.Name())
+	defer bus.Close()
+
+	// Publica eventos gravados no outbox
+	workers.NewOutboxRelay(db, bus, cfg).Start()
+
+	// Inicia consumers
+	utmifyConsumer := workers.NewUtmifyConsumer(db, bus, cfg)
+	if err := utmifyConsumer.Start(); err != nil {
+		log.Printf("⚠️ Erro ao iniciar UtmifyConsumer: %v", err)
 	}
 
+	// Expira pedidos com PIX vencido
+	expirationService := services.NewExpirationService(db, bus, cfg)
+	workers.NewExpirationSweeper(expirationService, cfg).Start()
+
+	// Consulta no gateway os pedidos pendentes cujo webhook pode ter se perdido
+	webhookService := services.NewWebhookService(db, redisClient, bus, cfg)
+	statusPollService := services.NewStatusPollService(db, webhookService, gateways.NewDefaultRegistry(cfg), cfg)
+	workers.NewStatusPoller(statusPollService, cfg).Start()
+
+	// Entrega os webhooks gravados em webhook_deliveries
+	workers.NewWebhookDispatcher(db, cfg).Start()
+
 	// Configura modo do Gin
 	if cfg.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	// Inicializa router
-	r := router.Setup(db, redisClient, rabbitMQ, cfg)
+	r := router.Setup(db, redisClient, bus, cfg)
 
 	// Inicia servidor
 	port := os.Getenv("PORT")
@@ -0,0 +1,94 @@
+// Comando deadletter: inspeciona, reprocessa ou apaga mensagens das filas .dlq
+// do barramento configurado em EVENT_BUS.
+//
+//	go run ./cmd/deadletter                                   # resumo de todas as filas
+//	go run ./cmd/deadletter -queue utmify.approved -peek 10
+//	go run ./cmd/deadletter -queue utmify.approved -replay 50
+//	go run ./cmd/deadletter -queue utmify.approved -purge
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/database"
+	"github.com/victtorkaiser/server-apis/internal/queue"
+)
+
+func main() {
+	queueName := flag.String("queue", "", "fila original (ex.: utmify.approved); vazio lista o resumo")
+	peek := flag.Int("peek", 0, "mostra até N mensagens da .dlq sem removê-las")
+	replay := flag.Int("replay", 0, "devolve até N mensagens da .dlq para a fila original")
+	purge := flag.Bool("purge", false, "apaga todas as mensagens da .dlq")
+	flag.Parse()
+
+	_ = godotenv.Load()
+	cfg := config.Load()
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	redisClient := database.ConnectRedis(cfg.RedisURL, cfg.RedisPassword, cfg.RedisDB)
+
+	// Mesmo barramento da API (EVENT_BUS)
+	bus, err := queue.Open(cfg, db, redisClient)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer bus.Close()
+
+	if *queueName == "" {
+		stats, err := bus.DeadLetterStats()
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "FILA\tTENTATIVAS\tINTERVALO\tEM RETENTATIVA\tDLQ")
+		for _, s := range stats {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%d\t%d\n", s.Queue, s.MaxAttempts, s.RetryDelay, s.Retrying, s.DeadLetters)
+		}
+		w.Flush()
+		return
+	}
+
+	switch {
+	case *peek > 0:
+		letters, err := bus.PeekDeadLetters(*queueName, *peek)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(letters); err != nil {
+			log.Fatal(err)
+		}
+
+	case *replay > 0:
+		replayed, err := bus.ReplayDeadLetters(context.Background(), *queueName, *replay)
+		fmt.Printf("%d mensagens devolvidas para %s\n", replayed, *queueName)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+	case *purge:
+		purged, err := bus.PurgeDeadLetters(*queueName)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%d mensagens apagadas de %s\n", purged, queue.DeadLetterQueue(*queueName))
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
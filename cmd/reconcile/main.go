@@ -0,0 +1,112 @@
+// Comando reconcile: concilia um extrato de liquidação do gateway com os pedidos.
+//
+//	go run ./cmd/reconcile -gateway blupay -file extrato.csv -from 2026-10-01 -to 2026-10-15
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/joho/godotenv"
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/database"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"github.com/victtorkaiser/server-apis/internal/services"
+	"github.com/victtorkaiser/server-apis/internal/settlement"
+)
+
+func main() {
+	gatewayName := flag.String("gateway", "", "gateway do extrato (blupay, quantumpay, mangofy, genesys)")
+	path := flag.String("file", "", "arquivo do extrato (.csv ou .json)")
+	format := flag.String("format", "", "csv ou json (padrão: extensão do arquivo)")
+	from := flag.String("from", "", "início do período para pedidos ausentes (YYYY-MM-DD ou RFC3339)")
+	to := flag.String("to", "", "fim do período (inclusive quando YYYY-MM-DD)")
+	asJSON := flag.Bool("json", false, "imprime o relatório completo em JSON")
+	flag.Parse()
+
+	if *gatewayName == "" || *path == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	_ = godotenv.Load()
+	cfg := config.Load()
+
+	gateway, ok := gateways.NewDefaultRegistry(cfg).Get(*gatewayName)
+	if !ok {
+		log.Fatalf("Gateway não suportado: %s", *gatewayName)
+	}
+
+	fileFormat := settlement.Format(*format)
+	if fileFormat == "" {
+		var err error
+		if fileFormat, err = settlement.FormatFromFilename(*path); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	periodFrom, periodTo, err := settlement.ParsePeriod(*from, *to)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	file, err := os.Open(*path)
+	if err != nil {
+		log.Fatalf("Erro ao abrir extrato: %v", err)
+	}
+	defer file.Close()
+
+	db, err := database.Connect(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Erro ao conectar ao banco de dados: %v", err)
+	}
+	if err := database.AutoMigrate(db); err != nil {
+		log.Fatalf("Erro ao executar migrations: %v", err)
+	}
+
+	report, err := services.NewSettlementService(db).Import(context.Background(), services.SettlementImport{
+		Gateway:  gateway.Name(),
+		Format:   fileFormat,
+		Filename: file.Name(),
+		From:     periodFrom,
+		To:       periodTo,
+	}, file)
+	if err != nil {
+		log.Fatalf("Erro ao conciliar: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	printReport(report)
+}
+
+// printReport imprime o resumo e só os itens que precisam de atenção
+func printReport(report *models.SettlementReport) {
+	fmt.Printf("Conciliação %s (%s)\n", report.ID, report.Gateway)
+	fmt.Printf("Linhas: %d  Conciliadas: %d  Valor divergente: %d  Extras: %d  Ausentes: %d\n\n",
+		report.Lines, report.Matched, report.Mismatched, report.Extra, report.Missing)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIPO\tLINHA\tTRANSACTION_ID\tEXTERNAL_REF\tPEDIDO\tEXTRATO\tSTATUS")
+	for _, item := range report.Items {
+		if item.Kind == models.SettlementMatched {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%d\t%d\t%s\n",
+			item.Kind, item.Row, item.TransactionID, item.ExternalRef, item.OrderAmount, item.SettledAmount, item.OrderStatus)
+	}
+	w.Flush()
+}
@@ -24,5 +24,17 @@ func AutoMigrate(db *gorm.DB) error {
 		&models.Customer{},
 		&models.Product{},
 		&models.TrackingParameter{},
+		&models.OutboxEvent{},
+		&models.BusMessage{},
+		&models.ProcessedEvent{},
+		&models.OrderStatusHistory{},
+		&models.Refund{},
+		&models.PaymentDiscrepancy{},
+		&models.SettlementReport{},
+		&models.SettlementItem{},
+		&models.WebhookDelivery{},
+		&models.WebhookDeliveryAttempt{},
+		&models.InboundWebhook{},
+		&models.WebhookEndpoint{},
 	)
 }
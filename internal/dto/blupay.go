@@ -1,20 +1,10 @@
 package dto
 
 // BluPay Request DTOs
-type BluPayRequest struct {
-	Amount       int                    `json:"amount" binding:"required,min=100"`
-	Name         string                 `json:"name"`
-	Email        string                 `json:"email"`
-	Document     string                 `json:"document"`
-	Phone        string                 `json:"phone"`
-	ExternalRef  string                 `json:"externalRef"`
-	WebhookURL   string                 `json:"webhook_url"`
-	UTMParams    map[string]interface{} `json:"utm_params"`
-}
-
 type BluPayAPIRequest struct {
 	Amount        int                 `json:"amount"`
 	PaymentMethod string              `json:"paymentMethod"`
+	Pix           BluPayPixConfig     `json:"pix"`
 	ExternalRef   string              `json:"externalRef"`
 	Customer      BluPayCustomer      `json:"customer"`
 	Items         []BluPayItem        `json:"items"`
@@ -23,6 +13,10 @@ type BluPayAPIRequest struct {
 	Metadata      map[string]string   `json:"metadata"`
 }
 
+type BluPayPixConfig struct {
+	ExpiresInDays int `json:"expiresInDays"`
+}
+
 type BluPayCustomer struct {
 	Name     string          `json:"name"`
 	Email    string          `json:"email"`
@@ -43,17 +37,6 @@ type BluPayItem struct {
 }
 
 // BluPay Response DTOs
-type BluPayResponse struct {
-	Success   bool   `json:"success"`
-	Token     string `json:"token"`
-	PixCode   string `json:"pixCode"`
-	QRCodeURL string `json:"qrCodeUrl"`
-	Amount    int    `json:"amount"`
-	Nome      string `json:"nome"`
-	CPF       string `json:"cpf"`
-	ExpiraEm  string `json:"expiraEm"`
-}
-
 type BluPayAPIResponse struct {
 	ID              string           `json:"id"`
 	Amount          int              `json:"amount"`
@@ -128,6 +111,7 @@ type BluPayWebhookData struct {
 	Status         string                 `json:"status"`
 	Amount         int                    `json:"amount"`
 	RefundedAmount int                    `json:"refundedAmount"`
+	Currency       string                 `json:"currency"`
 	Installments   int                    `json:"installments"`
 	PaymentMethod  string                 `json:"paymentMethod"`
 	CompanyID      string                 `json:"companyId"`
@@ -138,6 +122,7 @@ type BluPayWebhookData struct {
 	CreatedAt      string                 `json:"createdAt"`
 	UpdatedAt      string                 `json:"updatedAt"`
 	PostbackUrl    string                 `json:"postbackUrl"`
+	Fee            BluPayFee              `json:"fee"`
 }
 
 type BluPayWebhookCustomer struct {
@@ -1,17 +1,5 @@
 package dto
 
-// Genesys Request DTOs
-type GenesysRequest struct {
-	Amount     int                    `json:"amount" binding:"required,min=1"`
-	Name       string                 `json:"name"`
-	Email      string                 `json:"email"`
-	Document   string                 `json:"document"`
-	Phone      string                 `json:"phone"`
-	IP         string                 `json:"ip"`
-	WebhookURL string                 `json:"webhook_url"`
-	UTMParams  map[string]interface{} `json:"utm_params"`
-}
-
 // Genesys API Request (enviado para api.genesys.finance)
 type GenesysAPIRequest struct {
 	ExternalID    string                `json:"external_id"`
@@ -59,22 +47,12 @@ type GenesysPixResp struct {
 	Payload string `json:"payload"`
 }
 
-// Genesys Response (retornado ao nosso cliente)
-type GenesysResponse struct {
-	Success   bool   `json:"success"`
-	Token     string `json:"token"`
-	PixCode   string `json:"pix_code"`
-	QRCodeURL string `json:"qr_code_url"`
-	Amount    int    `json:"amount"`
-	Nome      string `json:"nome"`
-	CPF       string `json:"cpf"`
-}
-
 // Genesys Webhook Payload
 type GenesysWebhookPayload struct {
 	ID            string  `json:"id"`
 	ExternalID    string  `json:"external_id"`
-	TotalAmount   float64 `json:"total_amount"`
+	TotalAmount   float64 `json:"total_amount"` // em reais
+	Currency      string  `json:"currency"`
 	Status        string  `json:"status"`
 	PaymentMethod string  `json:"payment_method"`
 }
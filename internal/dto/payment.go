@@ -1,151 +1,75 @@
 package dto
 
-import "fmt"
+import "time"
 
 type CreatePaymentRequest struct {
-	Amount     int                    `json:"amount" binding:"required,min=1"`
-	Name       string                 `json:"name"`
-	Email      string                 `json:"email"`
-	Document   string                 `json:"document"`
-	Telephone  string                 `json:"telephone"`
-	UTMParams  map[string]interface{} `json:"utm_params"`
+	Amount    int                    `json:"amount" binding:"required,min=1"`
+	Name      string                 `json:"name"`
+	Email     string                 `json:"email"`
+	Document  string                 `json:"document"`
+	Telephone string                 `json:"telephone"`
+	ExpiresIn int                    `json:"expires_in" binding:"omitempty,min=60,max=2592000"` // em segundos
+	QRCodeB64 bool                   `json:"qr_code_base64"`                                    // inclui o QR Code como data URI na resposta
+	UTMParams map[string]interface{} `json:"utm_params"`
 }
 
 type CreatePaymentResponse struct {
-	Success   bool   `json:"success"`
-	Token     string `json:"token"`
-	PixCode   string `json:"pix_code,omitempty"`
-	QRCodeURL string `json:"qr_code_url,omitempty"`
-	Amount    int    `json:"amount"`
+	Success   bool       `json:"success"`
+	Token     string     `json:"token"`
+	Gateway   string     `json:"gateway,omitempty"`
+	PixCode   string     `json:"pix_code,omitempty"`
+	QRCodeURL string     `json:"qr_code_url,omitempty"`
+	QRCodeB64 string     `json:"qr_code_base64,omitempty"`
+	Amount    int        `json:"amount"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
 }
 
-// WebhookPayload - Suporta múltiplos formatos de webhook
-type WebhookPayload struct {
-	// Formato QuantumPay
-	Type     string              `json:"type"`
-	ObjectID interface{}         `json:"objectId"` // Pode ser string ou número
-	Data     *WebhookDataPayload `json:"data"`
-
-	// Formato BluPay
-	ID    string `json:"id"`    // ID do evento
-	Event string `json:"event"` // transaction.paid, transaction.refunded, etc
-
-	// Formato legado MangoFy
-	PaymentCode   string `json:"payment_code"`
-	PaymentStatus string `json:"payment_status"`
-	PaymentID     string `json:"paymentId"`
-	Status        string `json:"status"`
-}
-
-type WebhookDataPayload struct {
-	ID            interface{}            `json:"id"`
-	Status        string                 `json:"status"`
-	Amount        int                    `json:"amount"`
-	PaymentMethod string                 `json:"paymentMethod"`
-	PaidAt        *string                `json:"paidAt"`
-	CreatedAt     string                 `json:"createdAt"`
-	Customer      *WebhookCustomer       `json:"customer"`
-	Items         []WebhookItem          `json:"items"`
-	Fee           *WebhookFee            `json:"fee"`
-	Metadata      string                 `json:"metadata"`
-}
-
-type WebhookCustomer struct {
-	Name     string          `json:"name"`
-	Email    string          `json:"email"`
-	Phone    string          `json:"phone"`
-	Document *WebhookDocument `json:"document"`
-}
-
-type WebhookDocument struct {
-	Type   string `json:"type"`
-	Number string `json:"number"`
-}
-
-type WebhookItem struct {
-	Title       string `json:"title"`
-	Quantity    int    `json:"quantity"`
-	UnitPrice   int    `json:"unitPrice"`
-	ExternalRef string `json:"externalRef"`
-}
-
-type WebhookFee struct {
-	NetAmount    int `json:"netAmount"`
-	FixedAmount  int `json:"fixedAmount"`
-	EstimatedFee int `json:"estimatedFee"`
+// GatewayPaymentRequest - Request de /api/payment/:gateway (comum a todos os gateways)
+type GatewayPaymentRequest struct {
+	Amount      int                    `json:"amount" binding:"required,min=1"`
+	Name        string                 `json:"name"`
+	Email       string                 `json:"email"`
+	Document    string                 `json:"document"`
+	Phone       string                 `json:"phone"`
+	Telephone   string                 `json:"telephone"` // Formato legado QuantumPay
+	IP          string                 `json:"ip"`
+	ExternalRef string                 `json:"externalRef"`
+	WebhookURL  string                 `json:"webhook_url"`
+	ExpiresIn   int                    `json:"expires_in" binding:"omitempty,min=60,max=2592000"` // em segundos
+	QRCodeB64   bool                   `json:"qr_code_base64"`                                    // inclui o QR Code como data URI na resposta
+	UTMParams   map[string]interface{} `json:"utm_params"`
 }
 
-func (w *WebhookPayload) GetPaymentCode() string {
-	// Formato BluPay (usa objectId do webhook)
-	if w.Event != "" && w.ObjectID != nil {
-		switch v := w.ObjectID.(type) {
-		case string:
-			return v
-		case float64:
-			return fmt.Sprintf("%.0f", v)
-		case int:
-			return fmt.Sprintf("%d", v)
-		default:
-			return fmt.Sprintf("%v", v)
-		}
+// GetPhone retorna o telefone aceitando os dois nomes de campo
+func (r *GatewayPaymentRequest) GetPhone() string {
+	if r.Phone != "" {
+		return r.Phone
 	}
-
-	// Formato QuantumPay
-	if w.ObjectID != nil {
-		switch v := w.ObjectID.(type) {
-		case string:
-			return v
-		case float64:
-			return fmt.Sprintf("%.0f", v)
-		case int:
-			return fmt.Sprintf("%d", v)
-		default:
-			return fmt.Sprintf("%v", v)
-		}
-	}
-
-	// Formato BluPay - data.id
-	if w.Data != nil && w.Data.ID != nil {
-		switch v := w.Data.ID.(type) {
-		case string:
-			return v
-		case float64:
-			return fmt.Sprintf("%.0f", v)
-		case int:
-			return fmt.Sprintf("%d", v)
-		default:
-			return fmt.Sprintf("%v", v)
-		}
-	}
-
-	// Formato legado
-	if w.PaymentCode != "" {
-		return w.PaymentCode
-	}
-	return w.PaymentID
+	return r.Telephone
 }
 
-func (w *WebhookPayload) GetStatus() string {
-	// Formato BluPay (extrai status do event)
-	if w.Event != "" {
-		switch w.Event {
-		case "transaction.paid":
-			return "paid"
-		case "transaction.refunded":
-			return "refunded"
-		case "transaction.cancelled":
-			return "cancelled"
-		}
-	}
-
-	// Formato QuantumPay / BluPay data.status
-	if w.Data != nil && w.Data.Status != "" {
-		return w.Data.Status
-	}
+// GatewayPaymentResponse - Response de /api/payment/:gateway
+type GatewayPaymentResponse struct {
+	Success   bool       `json:"success"`
+	Gateway   string     `json:"gateway"`
+	Token     string     `json:"token"`
+	PixCode   string     `json:"pixCode"`
+	QRCodeURL string     `json:"qrCodeUrl"`
+	QRCodeB64 string     `json:"qrCodeBase64,omitempty"` // data:image/png;base64,...
+	Amount    int        `json:"amount"`
+	Nome      string     `json:"nome"`
+	CPF       string     `json:"cpf"`
+	ExpiraEm  string     `json:"expiraEm,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	Txid      string     `json:"txid,omitempty"`
+
+	// Mantidos por compatibilidade com clientes das rotas MangoFy/Genesys
+	PixCodeLegacy   string `json:"pix_code"`
+	QRCodeURLLegacy string `json:"qr_code_url"`
+}
 
-	// Formato legado
-	if w.PaymentStatus != "" {
-		return w.PaymentStatus
-	}
-	return w.Status
+// RefundRequest - Request de POST /api/v1/payments/:id/refund (sem amount estorna o saldo)
+type RefundRequest struct {
+	Amount int    `json:"amount" binding:"omitempty,min=1"`
+	Reason string `json:"reason"`
 }
@@ -1,16 +1,6 @@
 package dto
 
 // QuantumPay Request DTOs
-type QuantumPayRequest struct {
-	Amount     int                    `json:"amount" binding:"required,min=1"`
-	Name       string                 `json:"name"`
-	Email      string                 `json:"email"`
-	Document   string                 `json:"document"`
-	Telephone  string                 `json:"telephone"`
-	WebhookURL string                 `json:"webhook_url"`
-	UTMParams  map[string]interface{} `json:"utm_params"`
-}
-
 type QuantumPayAPIRequest struct {
 	Amount        int                    `json:"amount"`
 	PaymentMethod string                 `json:"paymentMethod"`
@@ -47,20 +37,10 @@ type QuantumPayItem struct {
 }
 
 // QuantumPay Response DTOs
-type QuantumPayResponse struct {
-	Success   bool   `json:"success"`
-	Token     string `json:"token"`
-	PixCode   string `json:"pixCode"`
-	QRCodeURL string `json:"qrCodeUrl"`
-	Amount    int    `json:"amount"`
-	Nome      string `json:"nome"`
-	CPF       string `json:"cpf"`
-	ExpiraEm  string `json:"expiraEm"`
-	Txid      string `json:"txid,omitempty"`
-}
-
 type QuantumPayAPIResponse struct {
-	ID  interface{}         `json:"id"` // Pode ser string ou número
+	ID     interface{}         `json:"id"` // Pode ser string ou número
+	Status string              `json:"status"`
+	Amount int                 `json:"amount"` // em centavos
 	Pix QuantumPayPixResult `json:"pix"`
 	Fee struct {
 		Amount int `json:"amount"`
@@ -74,3 +54,30 @@ type QuantumPayPixResult struct {
 	End2EndID  string `json:"end2EndId"`
 	Txid       string `json:"txid"`
 }
+
+// QuantumPay Webhook DTOs
+type QuantumPayWebhook struct {
+	ID       string                `json:"id"`
+	Type     string                `json:"type"`
+	ObjectID interface{}           `json:"objectId"` // Pode ser string ou número
+	Data     QuantumPayWebhookData `json:"data"`
+}
+
+type QuantumPayWebhookData struct {
+	ID             interface{}          `json:"id"` // Pode ser string ou número
+	Status         string               `json:"status"`
+	Amount         int                  `json:"amount"`
+	RefundedAmount int                  `json:"refundedAmount"`
+	Currency       string               `json:"currency"`
+	PaymentMethod  string               `json:"paymentMethod"`
+	PaidAt         *string              `json:"paidAt"`
+	CreatedAt      string               `json:"createdAt"`
+	Fee            QuantumPayWebhookFee `json:"fee"`
+	Metadata       string               `json:"metadata"`
+}
+
+type QuantumPayWebhookFee struct {
+	NetAmount    int `json:"netAmount"`
+	FixedAmount  int `json:"fixedAmount"`
+	EstimatedFee int `json:"estimatedFee"`
+}
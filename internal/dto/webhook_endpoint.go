@@ -0,0 +1,20 @@
+package dto
+
+import "github.com/victtorkaiser/server-apis/internal/models"
+
+// WebhookEndpointRequest - Request de POST /api/v1/webhook-endpoints
+type WebhookEndpointRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Events []string `json:"events"` // vazio = todos os eventos do catálogo
+}
+
+// WebhookEventsRequest - Request de PUT /api/v1/webhook-endpoints/:id/events
+type WebhookEventsRequest struct {
+	Events []string `json:"events"`
+}
+
+// WebhookEndpointResponse - Endpoint com o segredo em claro (só no cadastro e na rotação)
+type WebhookEndpointResponse struct {
+	*models.WebhookEndpoint
+	Secret string `json:"secret"`
+}
@@ -0,0 +1,78 @@
+// Package events define o catálogo de eventos do ciclo de vida do pedido,
+// usados nos webhooks para lojistas e nas filas.
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+// Type é o nome de um evento do catálogo (ex: payment.approved)
+type Type string
+
+const (
+	PaymentCreated   Type = "payment.created"
+	PaymentApproved  Type = "payment.approved"
+	PaymentRefunded  Type = "payment.refunded"
+	PaymentCancelled Type = "payment.cancelled"
+	PaymentExpired   Type = "payment.expired"
+	// PaymentNeedsReview é o alerta de pagamento com valor ou moeda divergente do pedido
+	PaymentNeedsReview Type = "payment.needs_review"
+)
+
+// Catalog lista todos os eventos emitidos
+var Catalog = []Type{
+	PaymentCreated,
+	PaymentApproved,
+	PaymentRefunded,
+	PaymentCancelled,
+	PaymentExpired,
+	PaymentNeedsReview,
+}
+
+func (t Type) String() string {
+	return string(t)
+}
+
+func (t Type) Valid() bool {
+	for _, known := range Catalog {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
+// ForStatus devolve o evento emitido quando o pedido entra no status informado.
+// pending e waiting_payment não geram evento.
+func ForStatus(status models.OrderStatus) (Type, bool) {
+	switch status {
+	case models.OrderStatusApproved, models.OrderStatusPaid:
+		return PaymentApproved, true
+	case models.OrderStatusRefunded, models.OrderStatusPartiallyRefunded:
+		return PaymentRefunded, true
+	case models.OrderStatusCancelled:
+		return PaymentCancelled, true
+	case models.OrderStatusExpired:
+		return PaymentExpired, true
+	case models.OrderStatusNeedsReview:
+		return PaymentNeedsReview, true
+	default:
+		return "", false
+	}
+}
+
+// ParseTypes valida uma lista de nomes de evento
+func ParseTypes(names []string) ([]Type, error) {
+	types := make([]Type, 0, len(names))
+	for _, name := range names {
+		t := Type(strings.ToLower(strings.TrimSpace(name)))
+		if !t.Valid() {
+			return nil, fmt.Errorf("evento desconhecido: %q", name)
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
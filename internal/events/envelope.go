@@ -0,0 +1,107 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SchemaVersion é a versão atual do envelope e dos payloads.
+// Mudança incompatível em um payload sobe a versão; consumers antigos
+// recusam a mensagem (vai para retentativa/DLQ) em vez de interpretá-la errado.
+const SchemaVersion = 1
+
+// Headers com os metadados do envelope (AMQP headers / campos do Redis Stream)
+const (
+	HeaderEventID       = "x-event-id"
+	HeaderEventType     = "x-event-type"
+	HeaderSchemaVersion = "x-schema-version"
+	HeaderOccurredAt    = "x-occurred-at"
+	HeaderCorrelationID = "x-correlation-id"
+)
+
+var (
+	ErrInvalidEnvelope   = errors.New("envelope de evento inválido")
+	ErrUnsupportedSchema = errors.New("versão de schema não suportada")
+)
+
+// Envelope é o corpo de toda mensagem publicada nas filas.
+// CorrelationID é o id do pedido: liga todos os eventos do mesmo pedido.
+type Envelope struct {
+	EventID       string          `json:"event_id"`
+	Type          Type            `json:"type"`
+	SchemaVersion int             `json:"schema_version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	CorrelationID string          `json:"correlation_id,omitempty"`
+	Payload       json.RawMessage `json:"payload"`
+}
+
+// NewEnvelope serializa o payload e monta o envelope com um event_id novo
+func NewEnvelope(t Type, correlationID string, payload interface{}) (*Envelope, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar payload de %s: %w", t, err)
+	}
+
+	return &Envelope{
+		EventID:       uuid.New().String(),
+		Type:          t,
+		SchemaVersion: SchemaVersion,
+		OccurredAt:    time.Now().UTC(),
+		CorrelationID: correlationID,
+		Payload:       body,
+	}, nil
+}
+
+// ParseEnvelope lê uma mensagem da fila. Mensagens anteriores ao envelope
+// (payload puro, sem event_id) voltam com SchemaVersion 0 e o corpo inteiro em Payload.
+func ParseEnvelope(data []byte) (*Envelope, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(data, &envelope); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+
+	if envelope.EventID == "" {
+		return &Envelope{Payload: data}, nil
+	}
+
+	if envelope.Type == "" || len(envelope.Payload) == 0 {
+		return nil, fmt.Errorf("%w: type e payload são obrigatórios", ErrInvalidEnvelope)
+	}
+
+	return &envelope, nil
+}
+
+// Decode preenche v com o payload, recusando versões mais novas que SchemaVersion
+func (e *Envelope) Decode(v interface{}) error {
+	if e.SchemaVersion > SchemaVersion {
+		return fmt.Errorf("%w: %s v%d (suportada até v%d)", ErrUnsupportedSchema, e.Type, e.SchemaVersion, SchemaVersion)
+	}
+
+	if err := json.Unmarshal(e.Payload, v); err != nil {
+		return fmt.Errorf("%w: payload de %s: %v", ErrInvalidEnvelope, e.Type, err)
+	}
+	return nil
+}
+
+// MessageID é o id da mensagem no barramento (AMQP message-id)
+func (e *Envelope) MessageID() string {
+	return e.EventID
+}
+
+// Headers devolve os metadados do envelope para os headers da mensagem
+func (e *Envelope) Headers() map[string]interface{} {
+	headers := map[string]interface{}{
+		HeaderEventID:       e.EventID,
+		HeaderEventType:     e.Type.String(),
+		HeaderSchemaVersion: int32(e.SchemaVersion),
+		HeaderOccurredAt:    e.OccurredAt.Format(time.RFC3339Nano),
+	}
+	if e.CorrelationID != "" {
+		headers[HeaderCorrelationID] = e.CorrelationID
+	}
+	return headers
+}
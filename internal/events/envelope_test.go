@@ -0,0 +1,114 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	orderID := uuid.New()
+	envelope, err := NewEnvelope(PaymentCreated, orderID.String(), PaymentCreatedPayload{OrderID: orderID, Amount: 2790, Gateway: "blupay"})
+	if err != nil {
+		t.Fatalf("NewEnvelope() = %v", err)
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+
+	parsed, err := ParseEnvelope(body)
+	if err != nil {
+		t.Fatalf("ParseEnvelope() = %v", err)
+	}
+	if parsed.EventID != envelope.EventID || parsed.Type != PaymentCreated || parsed.SchemaVersion != SchemaVersion || parsed.CorrelationID != orderID.String() {
+		t.Errorf("envelope lido = %+v, esperado %+v", parsed, envelope)
+	}
+
+	var payload PaymentCreatedPayload
+	if err := parsed.Decode(&payload); err != nil {
+		t.Fatalf("Decode() = %v", err)
+	}
+	if payload.OrderID != orderID || payload.Amount != 2790 || payload.Gateway != "blupay" {
+		t.Errorf("payload = %+v", payload)
+	}
+
+	headers := parsed.Headers()
+	if headers[HeaderEventID] != envelope.EventID || headers[HeaderSchemaVersion] != int32(SchemaVersion) || headers[HeaderCorrelationID] != orderID.String() {
+		t.Errorf("headers = %v", headers)
+	}
+}
+
+func TestParseEnvelope(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		wantErr     error
+		wantVersion int
+		wantPayload string
+	}{
+		{
+			name:        "envelope v1",
+			data:        `{"event_id":"e1","type":"payment.approved","schema_version":1,"occurred_at":"2026-10-17T12:00:00Z","payload":{"amount":100}}`,
+			wantVersion: 1,
+			wantPayload: `{"amount":100}`,
+		},
+		{
+			name:        "mensagem antiga sem envelope",
+			data:        `{"order_id":"abc","amount":100}`,
+			wantVersion: 0,
+			wantPayload: `{"order_id":"abc","amount":100}`,
+		},
+		{"envelope sem type", `{"event_id":"e1","payload":{}}`, ErrInvalidEnvelope, 0, ""},
+		{"envelope sem payload", `{"event_id":"e1","type":"payment.approved"}`, ErrInvalidEnvelope, 0, ""},
+		{"JSON inválido", `{"event_id":`, ErrInvalidEnvelope, 0, ""},
+		{"não é objeto", `[1,2]`, ErrInvalidEnvelope, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			envelope, err := ParseEnvelope([]byte(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseEnvelope() = %v, esperado %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if envelope.SchemaVersion != tt.wantVersion {
+				t.Errorf("SchemaVersion = %d, esperado %d", envelope.SchemaVersion, tt.wantVersion)
+			}
+			if string(envelope.Payload) != tt.wantPayload {
+				t.Errorf("Payload = %s, esperado %s", envelope.Payload, tt.wantPayload)
+			}
+		})
+	}
+}
+
+func TestEnvelopeDecode(t *testing.T) {
+	tests := []struct {
+		name     string
+		envelope Envelope
+		wantErr  error
+	}{
+		{"versão atual", Envelope{Type: PaymentCreated, SchemaVersion: SchemaVersion, Payload: json.RawMessage(`{"amount":100}`)}, nil},
+		{"mensagem antiga (v0)", Envelope{Payload: json.RawMessage(`{"amount":100}`)}, nil},
+		{"versão mais nova", Envelope{Type: PaymentCreated, SchemaVersion: SchemaVersion + 1, Payload: json.RawMessage(`{"amount":100}`)}, ErrUnsupportedSchema},
+		{"tipo incompatível", Envelope{Type: PaymentCreated, SchemaVersion: SchemaVersion, Payload: json.RawMessage(`{"amount":"cem"}`)}, ErrInvalidEnvelope},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var payload PaymentCreatedPayload
+			err := tt.envelope.Decode(&payload)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Decode() = %v, esperado %v", err, tt.wantErr)
+			}
+			if err == nil && payload.Amount != 100 {
+				t.Errorf("Amount = %d, esperado 100", payload.Amount)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+// Filas internas: não fazem parte do Catalog (não vão para os webhooks dos lojistas)
+const (
+	UtmifyPending  Type = "utmify.pending"
+	UtmifyApproved Type = "utmify.approved"
+)
+
+// Payloads das mensagens, versão SchemaVersion. Campos novos podem ser
+// adicionados sem subir a versão; remover ou mudar o tipo de um campo exige subir.
+
+// PaymentCreatedPayload é o payload de payment.created
+type PaymentCreatedPayload struct {
+	OrderID       uuid.UUID  `json:"order_id"`
+	TransactionID string     `json:"transaction_id"`
+	Amount        int        `json:"amount"` // em centavos
+	Platform      string     `json:"platform"`
+	Gateway       string     `json:"gateway"`
+	GatewayFee    int        `json:"gateway_fee"` // em centavos
+	NetAmount     int        `json:"net_amount"`  // em centavos
+	ExpiresAt     *time.Time `json:"expires_at,omitempty"`
+}
+
+// PaymentStatusPayload é o payload das mudanças de status aplicadas por ProcessEvent
+// (webhook do gateway ou polling): payment.approved, payment.cancelled, payment.expired,
+// payment.refunded e payment.needs_review
+type PaymentStatusPayload struct {
+	OrderID        uuid.UUID                  `json:"order_id"`
+	TransactionID  string                     `json:"transaction_id"`
+	PreviousStatus models.OrderStatus         `json:"previous_status"`
+	Status         models.OrderStatus         `json:"status"`
+	Platform       string                     `json:"platform"`
+	Discrepancy    *models.PaymentDiscrepancy `json:"discrepancy,omitempty"`
+}
+
+// PaymentExpiredPayload é o payload de payment.expired emitido pela expiração do PIX
+type PaymentExpiredPayload struct {
+	OrderID       uuid.UUID          `json:"order_id"`
+	TransactionID string             `json:"transaction_id"`
+	FromStatus    models.OrderStatus `json:"from_status"`
+	Status        models.OrderStatus `json:"status"`
+	Platform      string             `json:"platform"`
+	ExpiresAt     *time.Time         `json:"expires_at,omitempty"`
+}
+
+// PaymentRefundedPayload é o payload de payment.refunded emitido por um estorno
+type PaymentRefundedPayload struct {
+	OrderID        uuid.UUID          `json:"order_id"`
+	TransactionID  string             `json:"transaction_id"`
+	RefundID       uuid.UUID          `json:"refund_id"`
+	Amount         int                `json:"amount"`          // em centavos
+	RefundedAmount int                `json:"refunded_amount"` // total estornado do pedido, em centavos
+	Status         models.OrderStatus `json:"status"`
+	Platform       string             `json:"platform"`
+}
+
+// UtmifyPendingPayload é o payload de utmify.pending
+type UtmifyPendingPayload struct {
+	OrderID             uuid.UUID  `json:"order_id"`
+	TransactionID       string     `json:"transaction_id"`
+	CustomerID          uuid.UUID  `json:"customer_id"`
+	TrackingParameterID *uuid.UUID `json:"tracking_parameter_id,omitempty"`
+	Platform            string     `json:"platform"`
+	GatewayFee          int        `json:"gateway_fee"` // em centavos
+}
+
+// UtmifyApprovedPayload é o payload de utmify.approved
+type UtmifyApprovedPayload struct {
+	OrderID       uuid.UUID `json:"order_id"`
+	TransactionID string    `json:"transaction_id"`
+}
@@ -0,0 +1,248 @@
+package gateways
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+type BluPay struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewBluPay(cfg *config.Config) *BluPay {
+	return &BluPay{
+		cfg:    cfg,
+		client: newHTTPClient(),
+	}
+}
+
+func (g *BluPay) Name() string     { return "blupay" }
+func (g *BluPay) Platform() string { return "BluPay" }
+
+func (g *BluPay) CreateCharge(ctx context.Context, req *ChargeRequest) (*Charge, error) {
+	if req.Amount < 100 {
+		return nil, fmt.Errorf("%w: valor mínimo BluPay é 100 centavos", ErrInvalidCharge)
+	}
+
+	// Gera placa aleatória para referência externa se não fornecida
+	externalRef := req.ExternalRef
+	if externalRef == "" {
+		externalRef = fmt.Sprintf("ORD-%s", generatePlaca())
+	}
+
+	// Prepara metadata com UTM params
+	metadata := make(map[string]string)
+	for k, v := range req.UTMParams {
+		if str, ok := v.(string); ok {
+			metadata[k] = str
+		}
+	}
+	metadata["orderId"] = externalRef
+
+	expiresIn := expiresInDays(req.ExpiresIn)
+
+	// Prepara payload conforme API BluPay
+	payload := dto.BluPayAPIRequest{
+		Amount:        req.Amount,
+		PaymentMethod: "pix",
+		Pix: dto.BluPayPixConfig{
+			ExpiresInDays: expiresIn,
+		},
+		ExternalRef: externalRef,
+		Customer: dto.BluPayCustomer{
+			Name:  req.Name,
+			Email: req.Email,
+			Phone: req.Phone,
+			Document: dto.BluPayDocument{
+				Type:   "cpf",
+				Number: req.Document,
+			},
+		},
+		Items: []dto.BluPayItem{
+			{
+				Title:     g.cfg.BluPayProductName,
+				UnitPrice: req.Amount,
+				Quantity:  1,
+				Tangible:  false,
+			},
+		},
+		PostbackUrl:   g.cfg.BluPayWebhookURL,
+		WebhookSecret: g.cfg.BluPayWebhookSecret,
+		Metadata:      metadata,
+	}
+
+	body, _ := json.Marshal(payload)
+	log.Printf("📤 [BluPay] Request: %s", string(body))
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.BluPayAPIURL+"/api/v1/transactions", payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [BluPay] Response HTTP %d: %s", statusCode, string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("BluPay", statusCode, respBody)
+	}
+
+	var result dto.BluPayAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta BluPay: %w", err)
+	}
+
+	if result.ID == "" {
+		return nil, fmt.Errorf("ID não encontrado na resposta da API BluPay")
+	}
+
+	charge := &Charge{
+		TransactionID: result.ID,
+		ExternalRef:   externalRef,
+		PixCode:       result.Pix.QRCode,
+		Fee:           blupayFee(result.Fee),
+	}
+	// Prioriza o vencimento informado pela BluPay
+	if t, err := time.Parse(time.RFC3339, result.Pix.ExpiresAt); err == nil {
+		charge.ExpiresAt = &t
+	} else {
+		charge.ExpiresAt = expiresAfterDays(expiresIn)
+	}
+
+	return charge, nil
+}
+
+// blupayEvents são os eventos transaction.* com status implícito
+var blupayEvents = statusTable{
+	"transaction.waiting_payment":    models.OrderStatusWaitingPayment,
+	"transaction.paid":               models.OrderStatusApproved,
+	"transaction.refunded":           models.OrderStatusRefunded,
+	"transaction.partially_refunded": models.OrderStatusPartiallyRefunded,
+	"transaction.cancelled":          models.OrderStatusCancelled,
+	"transaction.canceled":           models.OrderStatusCancelled,
+	"transaction.expired":            models.OrderStatusExpired,
+}
+
+// blupayStatuses é o vocabulário de data.status (usado em transaction.updated e afins)
+var blupayStatuses = statusTable{
+	"pending":            models.OrderStatusPending,
+	"waiting_payment":    models.OrderStatusWaitingPayment,
+	"paid":               models.OrderStatusApproved,
+	"approved":           models.OrderStatusApproved,
+	"refunded":           models.OrderStatusRefunded,
+	"partially_refunded": models.OrderStatusPartiallyRefunded,
+	"canceled":           models.OrderStatusCancelled,
+	"cancelled":          models.OrderStatusCancelled,
+	"refused":            models.OrderStatusCancelled,
+	"expired":            models.OrderStatusExpired,
+}
+
+func (g *BluPay) ParseWebhook(body []byte) (*PaymentEvent, error) {
+	var webhook dto.BluPayWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("payload BluPay inválido: %w", err)
+	}
+
+	event := &PaymentEvent{
+		EventID:        webhook.ID,
+		TransactionID:  webhook.ObjectID,
+		RawStatus:      webhook.Data.Status,
+		RefundedAmount: webhook.Data.RefundedAmount,
+		Amount:         webhook.Data.Amount,
+		Currency:       webhook.Data.Currency,
+		Fee:            blupayFee(webhook.Data.Fee),
+	}
+	if event.TransactionID == "" {
+		event.TransactionID = webhook.Data.ID
+	}
+
+	status, err := blupayEvents.normalize(g.Platform(), webhook.Event)
+	if err == nil {
+		event.RawStatus = webhook.Event
+	} else {
+		status, err = blupayStatuses.normalize(g.Platform(), webhook.Data.Status)
+	}
+	if err != nil {
+		return event, fmt.Errorf("%w (event %q)", err, webhook.Event)
+	}
+
+	event.Status = status
+	return event, nil
+}
+
+func (g *BluPay) QueryStatus(ctx context.Context, transactionID string) (*ChargeStatus, error) {
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodGet, g.cfg.BluPayAPIURL+"/api/v1/transactions/"+transactionID, nil, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("BluPay", statusCode, respBody)
+	}
+
+	var result dto.BluPayAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta BluPay: %w", err)
+	}
+
+	charge, err := blupayStatuses.chargeStatus(g.Platform(), result.ID, result.Status)
+	charge.Amount = result.Amount
+	return charge, err
+}
+
+func (g *BluPay) Refund(ctx context.Context, transactionID string, amount int) (*RefundResult, error) {
+	payload := map[string]interface{}{
+		"amount": amount,
+	}
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.BluPayAPIURL+"/api/v1/transactions/"+transactionID+"/refund", payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [BluPay] Refund HTTP %d: %s", statusCode, string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, fmt.Errorf("erro no estorno BluPay: status %d - %s", statusCode, string(respBody))
+	}
+
+	var result dto.BluPayAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta BluPay: %w", err)
+	}
+
+	return &RefundResult{
+		RefundID: result.ID,
+		Pending:  result.Status != "refunded",
+	}, nil
+}
+
+// authHeaders monta a autenticação Basic Auth (secretKey:publicKey)
+func (g *BluPay) authHeaders() map[string]string {
+	auth := base64.StdEncoding.EncodeToString([]byte(g.cfg.BluPaySecretKey + ":" + g.cfg.BluPayPublicKey))
+	return map[string]string{
+		"Authorization": "Basic " + auth,
+	}
+}
+
+// blupayFee converte a tarifa da BluPay (resposta da criação e webhook)
+func blupayFee(fee dto.BluPayFee) *Fee {
+	if fee == (dto.BluPayFee{}) {
+		return nil
+	}
+
+	return &Fee{
+		Amount:           fee.EstimatedFee,
+		FixedAmount:      fee.FixedAmount,
+		SpreadPercentage: fee.SpreadPercentage,
+		NetAmount:        fee.NetAmount,
+	}
+}
@@ -0,0 +1,50 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+// APIError é a resposta HTTP de erro de um gateway
+type APIError struct {
+	Platform   string
+	StatusCode int
+	Body       string
+}
+
+func newAPIError(platform string, statusCode int, body []byte) *APIError {
+	return &APIError{
+		Platform:   platform,
+		StatusCode: statusCode,
+		Body:       string(body),
+	}
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("erro na API %s: status %d - %s", e.Platform, e.StatusCode, e.Body)
+}
+
+// IsRetryable indica se vale tentar a cobrança em outro gateway:
+// timeout, falha de conexão ou erro 5xx. Erros 4xx e validações não são repetidos.
+func IsRetryable(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= http.StatusInternalServerError || apiErr.StatusCode == http.StatusRequestTimeout
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	// Falha ao conectar: a requisição nem chegou ao gateway
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,96 @@
+package gateways
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+var (
+	// ErrUnknownGateway indica que o gateway não está registrado
+	ErrUnknownGateway = errors.New("gateway não suportado")
+	// ErrInvalidCharge indica que a requisição foi recusada antes de chegar ao gateway
+	ErrInvalidCharge = errors.New("cobrança inválida")
+	// ErrUnknownStatus indica status de webhook fora do vocabulário conhecido do gateway
+	ErrUnknownStatus = errors.New("status desconhecido")
+)
+
+// PaymentGateway é o contrato que cada PSP implementa.
+// Toda a orquestração (customer, order, eventos) fica no PaymentService;
+// o adapter só conversa com a API do gateway.
+type PaymentGateway interface {
+	// Name é a chave usada no registry e na rota /api/payment/:gateway
+	Name() string
+	// Platform é o nome gravado em Order.Platform
+	Platform() string
+	CreateCharge(ctx context.Context, req *ChargeRequest) (*Charge, error)
+	// ParseWebhook interpreta o payload tipado do gateway. Com status fora do
+	// vocabulário devolve o evento (sem Status) junto com ErrUnknownStatus.
+	ParseWebhook(body []byte) (*PaymentEvent, error)
+	QueryStatus(ctx context.Context, transactionID string) (*ChargeStatus, error)
+	// Refund estorna amount centavos da cobrança (parcial quando menor que o valor total)
+	Refund(ctx context.Context, transactionID string, amount int) (*RefundResult, error)
+}
+
+// ChargeRequest são os dados de uma cobrança PIX já normalizados
+type ChargeRequest struct {
+	Amount      int // em centavos
+	Name        string
+	Email       string
+	Document    string
+	Phone       string
+	IP          string
+	ExternalRef string
+	ExpiresIn   time.Duration // validade do PIX; cada gateway converte para o próprio formato
+	UTMParams   map[string]interface{}
+}
+
+// Charge é a cobrança criada no gateway
+type Charge struct {
+	TransactionID string
+	ExternalRef   string // referência externa enviada ao gateway (usada na conciliação)
+	PixCode       string
+	QRCodeURL     string // URL de QR Code fornecida pelo próprio gateway, se houver
+	Txid          string
+	Fee           *Fee // nil quando o gateway não informa tarifa na criação
+	ExpiresAt     *time.Time
+}
+
+// Fee é a tarifa cobrada pelo gateway sobre a transação
+type Fee struct {
+	Amount           int     // tarifa total, em centavos
+	FixedAmount      int     // parte fixa da tarifa, em centavos
+	SpreadPercentage float64 // parte percentual da tarifa
+	NetAmount        int     // valor líquido para o lojista, em centavos (0 = não informado)
+}
+
+// PaymentEvent é o webhook do gateway normalizado para o vocabulário interno
+type PaymentEvent struct {
+	Gateway        string // preenchido pelo handler com o nome do gateway
+	EventID        string // id do evento no gateway (usado para deduplicar), quando existir
+	TransactionID  string
+	Status         models.OrderStatus // status normalizado
+	RawStatus      string             // status/evento como enviado pelo gateway
+	RefundedAmount int                // total estornado informado pelo gateway, em centavos (0 = desconhecido)
+	Amount         int                // valor pago informado pelo gateway, em centavos (0 = não informado)
+	Currency       string             // moeda informada pelo gateway ("" = não informada)
+	Fee            *Fee               // tarifa informada no webhook, quando houver
+	Source         string             // origem: "webhook" (padrão) ou "polling"
+}
+
+// ChargeStatus é o status atual de uma cobrança consultada no gateway,
+// normalizado com o mesmo vocabulário dos webhooks
+type ChargeStatus struct {
+	TransactionID string
+	Status        models.OrderStatus
+	RawStatus     string
+	Amount        int // em centavos (0 = não informado)
+}
+
+// RefundResult é o estorno criado no gateway
+type RefundResult struct {
+	RefundID string
+	Pending  bool // true quando o gateway ainda vai confirmar o estorno
+}
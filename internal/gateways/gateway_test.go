@@ -0,0 +1,147 @@
+package gateways
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+func TestParseWebhook(t *testing.T) {
+	cfg := &config.Config{}
+	blupay := NewBluPay(cfg)
+	quantumpay := NewQuantumPay(cfg)
+	mangofy := NewMangoFy(cfg)
+	genesys := NewGenesys(cfg)
+
+	tests := []struct {
+		name    string
+		gateway PaymentGateway
+		body    string
+		want    *PaymentEvent
+		wantErr error
+		anyErr  bool
+	}{
+		{
+			name:    "BluPay transaction.paid com tarifa",
+			gateway: blupay,
+			body:    `{"id":"evt1","event":"transaction.paid","objectId":"tx1","data":{"id":"tx1","status":"paid","amount":2790,"currency":"BRL","fee":{"fixedAmount":50,"spreadPercentage":1.5,"estimatedFee":92,"netAmount":2698}}}`,
+			want: &PaymentEvent{
+				EventID: "evt1", TransactionID: "tx1", Status: models.OrderStatusApproved, RawStatus: "transaction.paid",
+				Amount: 2790, Currency: "BRL",
+				Fee: &Fee{Amount: 92, FixedAmount: 50, SpreadPercentage: 1.5, NetAmount: 2698},
+			},
+		},
+		{
+			name:    "BluPay evento genérico usa data.status e data.id",
+			gateway: blupay,
+			body:    `{"id":"evt2","event":"transaction.updated","data":{"id":"tx2","status":"partially_refunded","amount":2790,"refundedAmount":1000}}`,
+			want: &PaymentEvent{
+				EventID: "evt2", TransactionID: "tx2", Status: models.OrderStatusPartiallyRefunded, RawStatus: "partially_refunded",
+				Amount: 2790, RefundedAmount: 1000,
+			},
+		},
+		{
+			name:    "BluPay status desconhecido",
+			gateway: blupay,
+			body:    `{"id":"evt3","event":"transaction.updated","objectId":"tx3","data":{"status":"chargeback"}}`,
+			want:    &PaymentEvent{EventID: "evt3", TransactionID: "tx3", RawStatus: "chargeback"},
+			wantErr: ErrUnknownStatus,
+		},
+		{
+			name:    "QuantumPay com objectId numérico",
+			gateway: quantumpay,
+			body:    `{"id":"evt4","type":"transaction","objectId":12345,"data":{"id":12345,"status":"AUTHORIZED","amount":2790,"currency":"BRL","fee":{"netAmount":2700,"fixedAmount":90,"estimatedFee":90}}}`,
+			want: &PaymentEvent{
+				EventID: "evt4", TransactionID: "12345", Status: models.OrderStatusApproved, RawStatus: "AUTHORIZED",
+				Amount: 2790, Currency: "BRL",
+				Fee: &Fee{Amount: 90, FixedAmount: 90, NetAmount: 2700},
+			},
+		},
+		{
+			name:    "QuantumPay sem objectId usa data.id",
+			gateway: quantumpay,
+			body:    `{"id":"evt5","type":"transaction","data":{"id":"qp5","status":"refused"}}`,
+			want:    &PaymentEvent{EventID: "evt5", TransactionID: "qp5", Status: models.OrderStatusCancelled, RawStatus: "refused"},
+		},
+		{
+			name:    "QuantumPay status desconhecido",
+			gateway: quantumpay,
+			body:    `{"id":"evt6","objectId":"qp6","data":{"status":"in_protest"}}`,
+			want:    &PaymentEvent{EventID: "evt6", TransactionID: "qp6", RawStatus: "in_protest"},
+			wantErr: ErrUnknownStatus,
+		},
+		{
+			name:    "MangoFy aprovado",
+			gateway: mangofy,
+			body:    `{"payment_code":"mg1","payment_status":"approved"}`,
+			want:    &PaymentEvent{TransactionID: "mg1", Status: models.OrderStatusApproved, RawStatus: "approved"},
+		},
+		{
+			name:    "MangoFy status desconhecido",
+			gateway: mangofy,
+			body:    `{"payment_code":"mg2","payment_status":"chargeback"}`,
+			want:    &PaymentEvent{TransactionID: "mg2", RawStatus: "chargeback"},
+			wantErr: ErrUnknownStatus,
+		},
+		{
+			name:    "Genesys converte reais para centavos",
+			gateway: genesys,
+			body:    `{"id":"gn1","external_id":"ref1","total_amount":27.9,"currency":"BRL","status":"paid"}`,
+			want:    &PaymentEvent{TransactionID: "gn1", Status: models.OrderStatusApproved, RawStatus: "paid", Amount: 2790, Currency: "BRL"},
+		},
+		{
+			name:    "Genesys status desconhecido",
+			gateway: genesys,
+			body:    `{"id":"gn2","status":"disputed"}`,
+			want:    &PaymentEvent{TransactionID: "gn2", RawStatus: "disputed"},
+			wantErr: ErrUnknownStatus,
+		},
+		{name: "BluPay JSON inválido", gateway: blupay, body: `{`, anyErr: true},
+		{name: "QuantumPay JSON inválido", gateway: quantumpay, body: `[]`, anyErr: true},
+		{name: "MangoFy JSON inválido", gateway: mangofy, body: ``, anyErr: true},
+		{name: "Genesys JSON inválido", gateway: genesys, body: `{"total_amount":"27,90"}`, anyErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.gateway.ParseWebhook([]byte(tt.body))
+
+			if tt.anyErr {
+				if err == nil || got != nil {
+					t.Fatalf("ParseWebhook() = %+v, %v; esperado nil e erro", got, err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ParseWebhook() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWebhook() = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDetectWebhookGateway(t *testing.T) {
+	tests := []struct {
+		body   string
+		want   string
+		wantOK bool
+	}{
+		{`{"event":"transaction.paid","objectId":"tx1"}`, "blupay", true},
+		{`{"type":"transaction","objectId":123}`, "quantumpay", true},
+		{`{"payment_code":"mg1","payment_status":"approved"}`, "mangofy", true},
+		{`{"id":"gn1","status":"paid"}`, "", false},
+		{`not json`, "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := DetectWebhookGateway([]byte(tt.body))
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("DetectWebhookGateway(%s) = %q, %v; esperado %q, %v", tt.body, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
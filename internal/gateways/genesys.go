@@ -0,0 +1,202 @@
+package gateways
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+type Genesys struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewGenesys(cfg *config.Config) *Genesys {
+	return &Genesys{
+		cfg:    cfg,
+		client: newHTTPClient(),
+	}
+}
+
+func (g *Genesys) Name() string     { return "genesys" }
+func (g *Genesys) Platform() string { return "Genesys" }
+
+func (g *Genesys) CreateCharge(ctx context.Context, req *ChargeRequest) (*Charge, error) {
+	externalID := req.ExternalRef
+	if externalID == "" {
+		externalID = fmt.Sprintf("order_%s", uuid.New().String())
+	}
+
+	// Converte centavos para BRL (Genesys usa float em reais)
+	amountBRL := float64(req.Amount) / 100.0
+
+	ip := req.IP
+	if ip == "" {
+		ip = "177.0.0.1"
+	}
+
+	payload := dto.GenesysAPIRequest{
+		ExternalID:    externalID,
+		TotalAmount:   amountBRL,
+		PaymentMethod: "PIX",
+		WebhookURL:    fmt.Sprintf("%s/api/v1/webhooks/genesys", g.cfg.WebhookBaseURL),
+		Items: []dto.GenesysItem{
+			{
+				ID:          "1",
+				Title:       "Produto",
+				Description: "Produto digital",
+				Price:       amountBRL,
+				Quantity:    1,
+				IsPhysical:  false,
+			},
+		},
+		IP: ip,
+		Customer: dto.GenesysCustomer{
+			Name:         req.Name,
+			Email:        req.Email,
+			Phone:        req.Phone,
+			DocumentType: "CPF",
+			Document:     req.Document,
+		},
+	}
+
+	body, _ := json.Marshal(payload)
+	log.Printf("📤 [Genesys] Request: %s", string(body))
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.GenesysAPIURL+"/v1/transactions", payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [Genesys] Response HTTP %d: %s", statusCode, string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("Genesys", statusCode, respBody)
+	}
+
+	var result dto.GenesysAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta Genesys: %w", err)
+	}
+
+	if result.HasError {
+		return nil, fmt.Errorf("erro retornado pela API Genesys")
+	}
+
+	if result.ID == "" {
+		return nil, fmt.Errorf("ID não encontrado na resposta da API Genesys")
+	}
+
+	// Genesys não aceita validade no request: o pedido vence localmente no prazo pedido
+	expiresAt := time.Now().Add(req.ExpiresIn)
+
+	return &Charge{
+		TransactionID: result.ID,
+		ExternalRef:   externalID,
+		PixCode:       result.Pix.Payload,
+		ExpiresAt:     &expiresAt,
+	}, nil
+}
+
+// genesysStatuses é o vocabulário de status do webhook
+var genesysStatuses = statusTable{
+	"pending":         models.OrderStatusPending,
+	"waiting_payment": models.OrderStatusWaitingPayment,
+	"paid":            models.OrderStatusApproved,
+	"approved":        models.OrderStatusApproved,
+	"refunded":        models.OrderStatusRefunded,
+	"canceled":        models.OrderStatusCancelled,
+	"cancelled":       models.OrderStatusCancelled,
+	"expired":         models.OrderStatusExpired,
+}
+
+func (g *Genesys) ParseWebhook(body []byte) (*PaymentEvent, error) {
+	var webhook dto.GenesysWebhookPayload
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("payload Genesys inválido: %w", err)
+	}
+
+	log.Printf("📥 [Genesys Webhook] Recebido: id=%s status=%s amount=%.2f", webhook.ID, webhook.Status, webhook.TotalAmount)
+
+	event := &PaymentEvent{
+		TransactionID: webhook.ID,
+		RawStatus:     webhook.Status,
+		Amount:        int(math.Round(webhook.TotalAmount * 100)), // total_amount vem em reais
+		Currency:      webhook.Currency,
+	}
+
+	status, err := genesysStatuses.normalize(g.Platform(), webhook.Status)
+	if err != nil {
+		return event, err
+	}
+
+	event.Status = status
+	return event, nil
+}
+
+func (g *Genesys) QueryStatus(ctx context.Context, transactionID string) (*ChargeStatus, error) {
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodGet, g.cfg.GenesysAPIURL+"/v1/transactions/"+transactionID, nil, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("Genesys", statusCode, respBody)
+	}
+
+	var result dto.GenesysAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta Genesys: %w", err)
+	}
+
+	charge, err := genesysStatuses.chargeStatus(g.Platform(), result.ID, result.Status)
+	charge.Amount = int(math.Round(result.TotalValue * 100)) // total_value vem em reais
+	return charge, err
+}
+
+func (g *Genesys) Refund(ctx context.Context, transactionID string, amount int) (*RefundResult, error) {
+	// Genesys usa valores em reais
+	payload := map[string]interface{}{
+		"amount": float64(amount) / 100.0,
+	}
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.GenesysAPIURL+"/v1/transactions/"+transactionID+"/refund", payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [Genesys] Refund HTTP %d: %s", statusCode, string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, fmt.Errorf("erro no estorno Genesys: status %d - %s", statusCode, string(respBody))
+	}
+
+	var result dto.GenesysAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta Genesys: %w", err)
+	}
+
+	if result.HasError {
+		return nil, fmt.Errorf("erro retornado pela API Genesys")
+	}
+
+	return &RefundResult{
+		RefundID: result.ID,
+		Pending:  result.Status != "REFUNDED" && result.Status != "refunded",
+	}, nil
+}
+
+func (g *Genesys) authHeaders() map[string]string {
+	return map[string]string{
+		"api-secret": g.cfg.GenesysAPISecret,
+	}
+}
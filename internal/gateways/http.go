@@ -0,0 +1,101 @@
+package gateways
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 30 * time.Second}
+}
+
+// doRequest executa a chamada HTTP e devolve o status e o corpo da resposta
+func doRequest(ctx context.Context, client *http.Client, method, url string, payload interface{}, headers map[string]string) (int, []byte, error) {
+	var body io.Reader
+	if payload != nil {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, fmt.Errorf("erro ao serializar payload: %w", err)
+		}
+		body = bytes.NewBuffer(data)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, url, body)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	for k, v := range headers {
+		httpReq.Header.Set(k, v)
+	}
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, nil, fmt.Errorf("erro ao ler resposta: %w", err)
+	}
+
+	return resp.StatusCode, respBody, nil
+}
+
+func isSuccess(statusCode int) bool {
+	return statusCode == http.StatusOK || statusCode == http.StatusCreated
+}
+
+// formatID converte IDs que podem vir como string ou número
+func formatID(v interface{}) string {
+	switch id := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return id
+	case float64:
+		return fmt.Sprintf("%.0f", id)
+	case int:
+		return fmt.Sprintf("%d", id)
+	default:
+		return fmt.Sprintf("%v", id)
+	}
+}
+
+// generatePlaca gera uma placa aleatória usada como referência externa
+func generatePlaca() string {
+	letters := "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	placa := ""
+	for i := 0; i < 3; i++ {
+		placa += string(letters[rand.Intn(len(letters))])
+	}
+	for i := 0; i < 4; i++ {
+		placa += fmt.Sprintf("%d", rand.Intn(10))
+	}
+	return placa
+}
+
+// expiresInDays converte a validade para dias inteiros (arredonda para cima, mínimo 1),
+// formato usado pelos gateways que só aceitam expiração em dias
+func expiresInDays(d time.Duration) int {
+	days := int(math.Ceil(d.Hours() / 24))
+	if days < 1 {
+		return 1
+	}
+	return days
+}
+
+// expiresAfterDays calcula o vencimento efetivo de uma cobrança criada com expiresInDays
+func expiresAfterDays(days int) *time.Time {
+	t := time.Now().Add(time.Duration(days) * 24 * time.Hour)
+	return &t
+}
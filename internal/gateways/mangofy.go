@@ -0,0 +1,200 @@
+package gateways
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+type MangoFy struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewMangoFy(cfg *config.Config) *MangoFy {
+	return &MangoFy{
+		cfg:    cfg,
+		client: newHTTPClient(),
+	}
+}
+
+func (g *MangoFy) Name() string     { return "mangofy" }
+func (g *MangoFy) Platform() string { return "MangoFy" }
+
+func (g *MangoFy) CreateCharge(ctx context.Context, req *ChargeRequest) (*Charge, error) {
+	externalCode := req.ExternalRef
+	if externalCode == "" {
+		externalCode = fmt.Sprintf("order_%s", uuid.New().String())
+	}
+
+	ip := req.IP
+	if ip == "" {
+		ip = "177.0.0.1"
+	}
+
+	extra := req.UTMParams
+	if extra == nil {
+		extra = map[string]interface{}{}
+	}
+
+	expiresIn := expiresInDays(req.ExpiresIn)
+
+	payload := map[string]interface{}{
+		"store_code":      g.cfg.MangoFyAPIKey,
+		"external_code":   externalCode,
+		"payment_method":  "pix",
+		"payment_format":  "regular",
+		"installments":    1,
+		"payment_amount":  req.Amount,
+		"shipping_amount": 0,
+		"postback_url":    fmt.Sprintf("%s/api/v1/webhooks/mangofy", g.cfg.WebhookBaseURL),
+		"items": []map[string]interface{}{
+			{
+				"code":   "1",
+				"name":   "Produto",
+				"amount": req.Amount,
+				"total":  1,
+			},
+		},
+		"customer": map[string]interface{}{
+			"email":    req.Email,
+			"name":     req.Name,
+			"document": req.Document,
+			"phone":    req.Phone,
+			"ip":       ip,
+		},
+		"pix": map[string]interface{}{
+			"expires_in_days": expiresIn,
+		},
+		"extra": extra,
+	}
+
+	body, _ := json.Marshal(payload)
+	log.Printf("📤 [MangoFy] Request: %s", string(body))
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.MangoFyAPIURL, payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [MangoFy] Response HTTP %d: %s", statusCode, string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("MangoFy", statusCode, respBody)
+	}
+
+	var result dto.MangoFyAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta MangoFy: %w", err)
+	}
+
+	if result.PaymentCode == "" {
+		return nil, fmt.Errorf("payment_code não encontrado na resposta da API MangoFy")
+	}
+
+	// Extrai pix code (prioriza pix.pix_qrcode_text)
+	pixCode := result.Pix.PixQRCodeText
+	if pixCode == "" {
+		pixCode = result.PixCode
+	}
+
+	return &Charge{
+		TransactionID: result.PaymentCode,
+		ExternalRef:   externalCode,
+		PixCode:       pixCode,
+		ExpiresAt:     expiresAfterDays(expiresIn),
+	}, nil
+}
+
+// mangofyStatuses é o vocabulário de payment_status
+var mangofyStatuses = statusTable{
+	"pending":         models.OrderStatusPending,
+	"waiting_payment": models.OrderStatusWaitingPayment,
+	"approved":        models.OrderStatusApproved,
+	"paid":            models.OrderStatusApproved,
+	"refunded":        models.OrderStatusRefunded,
+	"canceled":        models.OrderStatusCancelled,
+	"cancelled":       models.OrderStatusCancelled,
+	"refused":         models.OrderStatusCancelled,
+	"expired":         models.OrderStatusExpired,
+}
+
+func (g *MangoFy) ParseWebhook(body []byte) (*PaymentEvent, error) {
+	var webhook dto.MangoFyWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("payload MangoFy inválido: %w", err)
+	}
+
+	event := &PaymentEvent{
+		TransactionID: webhook.PaymentCode,
+		RawStatus:     webhook.PaymentStatus,
+	}
+
+	status, err := mangofyStatuses.normalize(g.Platform(), webhook.PaymentStatus)
+	if err != nil {
+		return event, err
+	}
+
+	event.Status = status
+	return event, nil
+}
+
+func (g *MangoFy) QueryStatus(ctx context.Context, transactionID string) (*ChargeStatus, error) {
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodGet, g.cfg.MangoFyAPIURL+"/"+transactionID, nil, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("MangoFy", statusCode, respBody)
+	}
+
+	var result dto.MangoFyAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta MangoFy: %w", err)
+	}
+
+	return mangofyStatuses.chargeStatus(g.Platform(), result.PaymentCode, result.PaymentStatus)
+}
+
+func (g *MangoFy) Refund(ctx context.Context, transactionID string, amount int) (*RefundResult, error) {
+	payload := map[string]interface{}{
+		"store_code":    g.cfg.MangoFyAPIKey,
+		"refund_amount": amount,
+	}
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.MangoFyAPIURL+"/"+transactionID+"/refund", payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [MangoFy] Refund HTTP %d: %s", statusCode, string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, fmt.Errorf("erro no estorno MangoFy: status %d - %s", statusCode, string(respBody))
+	}
+
+	var result dto.MangoFyAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta MangoFy: %w", err)
+	}
+
+	return &RefundResult{
+		RefundID: result.PaymentCode,
+		Pending:  result.PaymentStatus != "refunded",
+	}, nil
+}
+
+func (g *MangoFy) authHeaders() map[string]string {
+	return map[string]string{
+		"Authorization": g.cfg.MangoFySecret,
+		"Store-Code":    g.cfg.MangoFyAPIKey,
+	}
+}
@@ -0,0 +1,235 @@
+package gateways
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+type QuantumPay struct {
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewQuantumPay(cfg *config.Config) *QuantumPay {
+	return &QuantumPay{
+		cfg:    cfg,
+		client: newHTTPClient(),
+	}
+}
+
+func (g *QuantumPay) Name() string     { return "quantumpay" }
+func (g *QuantumPay) Platform() string { return "QuantumPay" }
+
+func (g *QuantumPay) CreateCharge(ctx context.Context, req *ChargeRequest) (*Charge, error) {
+	// Gera placa aleatória para referência externa
+	placa := generatePlaca()
+	externalRef := fmt.Sprintf("md-%s-%d", placa, time.Now().Unix())
+
+	// Monta metadata com UTM params
+	metadataJSON, _ := json.Marshal(req.UTMParams)
+
+	ip := req.IP
+	if ip == "" {
+		ip = "127.0.0.1"
+	}
+
+	expiresIn := expiresInDays(req.ExpiresIn)
+
+	// Prepara payload conforme API QuantumPay
+	payload := dto.QuantumPayAPIRequest{
+		Amount:        req.Amount,
+		PaymentMethod: "pix",
+		Pix: dto.QuantumPayPixConfig{
+			ExpiresInDays: expiresIn,
+		},
+		Customer: dto.QuantumPayCustomer{
+			Name:  req.Name,
+			Email: req.Email,
+			Phone: req.Phone,
+			Document: dto.QuantumPayDocument{
+				Type:   "cpf",
+				Number: req.Document,
+			},
+			ExternalRef: externalRef,
+		},
+		Items: []dto.QuantumPayItem{
+			{
+				Title:       g.cfg.QuantumPayProductName,
+				UnitPrice:   req.Amount,
+				Quantity:    1,
+				Tangible:    false,
+				ExternalRef: fmt.Sprintf("IPVA-%s", placa),
+			},
+		},
+		Metadata: string(metadataJSON),
+		IP:       ip,
+	}
+
+	body, _ := json.Marshal(payload)
+	log.Printf("📤 [QuantumPay] Request: %s", string(body))
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.QuantumPayAPIURL, payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [QuantumPay] Response: %s", string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("QuantumPay", statusCode, respBody)
+	}
+
+	var result dto.QuantumPayAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, err
+	}
+
+	if result.ID == nil {
+		return nil, fmt.Errorf("ID não encontrado na resposta da API QuantumPay")
+	}
+
+	return &Charge{
+		TransactionID: formatID(result.ID),
+		ExternalRef:   externalRef,
+		PixCode:       result.Pix.QRCode,
+		QRCodeURL:     g.extractQRCodeURL(&result),
+		Txid:          g.extractTxid(&result),
+		Fee:           quantumpayFee(result.Fee.Amount, dto.QuantumPayWebhookFee{}),
+		ExpiresAt:     expiresAfterDays(expiresIn),
+	}, nil
+}
+
+// quantumpayStatuses é o vocabulário de data.status
+var quantumpayStatuses = statusTable{
+	"pending":            models.OrderStatusPending,
+	"waiting_payment":    models.OrderStatusWaitingPayment,
+	"paid":               models.OrderStatusApproved,
+	"approved":           models.OrderStatusApproved,
+	"authorized":         models.OrderStatusApproved,
+	"refunded":           models.OrderStatusRefunded,
+	"partially_refunded": models.OrderStatusPartiallyRefunded,
+	"canceled":           models.OrderStatusCancelled,
+	"cancelled":          models.OrderStatusCancelled,
+	"refused":            models.OrderStatusCancelled,
+	"expired":            models.OrderStatusExpired,
+}
+
+func (g *QuantumPay) ParseWebhook(body []byte) (*PaymentEvent, error) {
+	var webhook dto.QuantumPayWebhook
+	if err := json.Unmarshal(body, &webhook); err != nil {
+		return nil, fmt.Errorf("payload QuantumPay inválido: %w", err)
+	}
+
+	event := &PaymentEvent{
+		EventID:        webhook.ID,
+		TransactionID:  formatID(webhook.ObjectID),
+		RawStatus:      webhook.Data.Status,
+		RefundedAmount: webhook.Data.RefundedAmount,
+		Amount:         webhook.Data.Amount,
+		Currency:       webhook.Data.Currency,
+		Fee:            quantumpayFee(webhook.Data.Fee.EstimatedFee, webhook.Data.Fee),
+	}
+	if event.TransactionID == "" {
+		event.TransactionID = formatID(webhook.Data.ID)
+	}
+
+	status, err := quantumpayStatuses.normalize(g.Platform(), webhook.Data.Status)
+	if err != nil {
+		return event, err
+	}
+
+	event.Status = status
+	return event, nil
+}
+
+func (g *QuantumPay) QueryStatus(ctx context.Context, transactionID string) (*ChargeStatus, error) {
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodGet, g.cfg.QuantumPayAPIURL+"/"+transactionID, nil, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	if !isSuccess(statusCode) {
+		return nil, newAPIError("QuantumPay", statusCode, respBody)
+	}
+
+	var result dto.QuantumPayAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta QuantumPay: %w", err)
+	}
+
+	charge, err := quantumpayStatuses.chargeStatus(g.Platform(), formatID(result.ID), result.Status)
+	charge.Amount = result.Amount
+	return charge, err
+}
+
+func (g *QuantumPay) Refund(ctx context.Context, transactionID string, amount int) (*RefundResult, error) {
+	payload := map[string]interface{}{
+		"amount": amount,
+	}
+
+	statusCode, respBody, err := doRequest(ctx, g.client, http.MethodPost, g.cfg.QuantumPayAPIURL+"/"+transactionID+"/refund", payload, g.authHeaders())
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("📡 [QuantumPay] Refund HTTP %d: %s", statusCode, string(respBody))
+
+	if !isSuccess(statusCode) {
+		return nil, fmt.Errorf("erro no estorno QuantumPay: status %d - %s", statusCode, string(respBody))
+	}
+
+	var result dto.QuantumPayAPIResponse
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("erro ao decodificar resposta QuantumPay: %w", err)
+	}
+
+	return &RefundResult{
+		RefundID: formatID(result.ID),
+		Pending:  result.Status != "refunded",
+	}, nil
+}
+
+// authHeaders monta a autenticação Basic com secret key
+func (g *QuantumPay) authHeaders() map[string]string {
+	auth := base64.StdEncoding.EncodeToString([]byte(g.cfg.QuantumPaySecretKey + ":x"))
+	return map[string]string{
+		"Authorization": "Basic " + auth,
+		"Accept":        "application/json",
+	}
+}
+
+func (g *QuantumPay) extractQRCodeURL(resp *dto.QuantumPayAPIResponse) string {
+	if resp.Pix.ReceiptURL != "" {
+		return resp.Pix.ReceiptURL
+	}
+	return resp.Pix.QRCodeURL
+}
+
+func (g *QuantumPay) extractTxid(resp *dto.QuantumPayAPIResponse) string {
+	if resp.Pix.End2EndID != "" {
+		return resp.Pix.End2EndID
+	}
+	return resp.Pix.Txid
+}
+
+// quantumpayFee monta a tarifa a partir de fee.amount (criação) ou do fee do webhook
+func quantumpayFee(amount int, breakdown dto.QuantumPayWebhookFee) *Fee {
+	if amount == 0 && breakdown == (dto.QuantumPayWebhookFee{}) {
+		return nil
+	}
+
+	return &Fee{
+		Amount:      amount,
+		FixedAmount: breakdown.FixedAmount,
+		NetAmount:   breakdown.NetAmount,
+	}
+}
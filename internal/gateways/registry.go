@@ -0,0 +1,67 @@
+package gateways
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+)
+
+// Registry mantém os gateways disponíveis indexados pelo nome
+type Registry struct {
+	mu       sync.RWMutex
+	gateways map[string]PaymentGateway
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		gateways: make(map[string]PaymentGateway),
+	}
+}
+
+// NewDefaultRegistry registra todos os gateways suportados
+func NewDefaultRegistry(cfg *config.Config) *Registry {
+	r := NewRegistry()
+	r.Register(NewQuantumPay(cfg))
+	r.Register(NewBluPay(cfg))
+	r.Register(NewMangoFy(cfg))
+	r.Register(NewGenesys(cfg))
+	return r
+}
+
+func (r *Registry) Register(gateway PaymentGateway) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.gateways[strings.ToLower(gateway.Name())] = gateway
+}
+
+func (r *Registry) Get(name string) (PaymentGateway, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	gateway, ok := r.gateways[strings.ToLower(name)]
+	return gateway, ok
+}
+
+// GetByPlatform busca o gateway pelo nome gravado em Order.Platform
+func (r *Registry) GetByPlatform(platform string) (PaymentGateway, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, gateway := range r.gateways {
+		if strings.EqualFold(gateway.Platform(), platform) {
+			return gateway, true
+		}
+	}
+	return nil, false
+}
+
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.gateways))
+	for name := range r.gateways {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,171 @@
+package gateways
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+)
+
+const (
+	RoutingPriority = "priority"
+	RoutingWeighted = "weighted"
+)
+
+// routingMetrics expõe em /debug/vars as tentativas por gateway
+// (chaves <gateway>.attempts, .success, .failure e .failover)
+var routingMetrics = expvar.NewMap("gateway_routing")
+
+// Route é um gateway da lista de roteamento com seu peso
+type Route struct {
+	Name   string
+	Weight int
+}
+
+// RoutedCharge é a cobrança criada pelo Router e o caminho percorrido até ela
+type RoutedCharge struct {
+	Gateway PaymentGateway
+	Charge  *Charge
+	Failed  []string // gateways que falharam antes (em ordem)
+}
+
+// Router escolhe a ordem dos gateways e faz failover em erros temporários
+type Router struct {
+	registry *Registry
+	routes   []Route
+	mode     string
+}
+
+// NewRouter monta o roteamento a partir de GATEWAY_ROUTING ("blupay:70,quantumpay:30").
+// Sem lista configurada usa apenas o gateway padrão.
+func NewRouter(registry *Registry, cfg *config.Config) *Router {
+	routes := ParseRoutes(cfg.GatewayRouting)
+	if len(routes) == 0 {
+		routes = []Route{{Name: cfg.DefaultGateway, Weight: 1}}
+	}
+
+	mode := strings.ToLower(cfg.GatewayRoutingMode)
+	if mode != RoutingWeighted {
+		mode = RoutingPriority
+	}
+
+	return &Router{
+		registry: registry,
+		routes:   routes,
+		mode:     mode,
+	}
+}
+
+// ParseRoutes lê a lista "nome[:peso],..." (peso padrão 1)
+func ParseRoutes(spec string) []Route {
+	var routes []Route
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, weightStr, _ := strings.Cut(part, ":")
+		weight := 1
+		if weightStr != "" {
+			if w, err := strconv.Atoi(strings.TrimSpace(weightStr)); err == nil && w > 0 {
+				weight = w
+			}
+		}
+		routes = append(routes, Route{Name: strings.ToLower(strings.TrimSpace(name)), Weight: weight})
+	}
+	return routes
+}
+
+// Plan devolve os gateways na ordem em que serão tentados.
+// priority segue a ordem configurada; weighted sorteia a ordem proporcionalmente ao peso.
+func (r *Router) Plan() []PaymentGateway {
+	routes := r.routes
+	if r.mode == RoutingWeighted {
+		routes = weightedOrder(routes)
+	}
+
+	plan := make([]PaymentGateway, 0, len(routes))
+	for _, route := range routes {
+		gateway, ok := r.registry.Get(route.Name)
+		if !ok {
+			log.Printf("⚠️ [Router] Gateway %q do roteamento não está registrado", route.Name)
+			continue
+		}
+		plan = append(plan, gateway)
+	}
+	return plan
+}
+
+// Route cria a cobrança seguindo o plano de roteamento
+func (r *Router) Route(ctx context.Context, req *ChargeRequest) (*RoutedCharge, error) {
+	plan := r.Plan()
+	if len(plan) == 0 {
+		return nil, fmt.Errorf("%w: nenhum gateway no roteamento", ErrUnknownGateway)
+	}
+	return r.CreateCharge(ctx, req, plan...)
+}
+
+// CreateCharge tenta os gateways em ordem, passando para o próximo apenas em erro temporário
+func (r *Router) CreateCharge(ctx context.Context, req *ChargeRequest, candidates ...PaymentGateway) (*RoutedCharge, error) {
+	routed := &RoutedCharge{}
+
+	for i, gateway := range candidates {
+		routingMetrics.Add(gateway.Name()+".attempts", 1)
+
+		charge, err := gateway.CreateCharge(ctx, req)
+		if err == nil {
+			routingMetrics.Add(gateway.Name()+".success", 1)
+			if len(routed.Failed) > 0 {
+				log.Printf("🔀 [Router] Cobrança criada no %s após falha em %s", gateway.Platform(), strings.Join(routed.Failed, ", "))
+			}
+			routed.Gateway = gateway
+			routed.Charge = charge
+			return routed, nil
+		}
+
+		routingMetrics.Add(gateway.Name()+".failure", 1)
+		err = fmt.Errorf("erro ao chamar API %s: %w", gateway.Platform(), err)
+
+		last := i == len(candidates)-1
+		if last || !IsRetryable(err) || ctx.Err() != nil {
+			log.Printf("❌ [Router] %s falhou, sem failover: %v", gateway.Platform(), err)
+			return nil, err
+		}
+
+		routingMetrics.Add(gateway.Name()+".failover", 1)
+		routed.Failed = append(routed.Failed, gateway.Name())
+		log.Printf("🔀 [Router] %s falhou (%v), tentando %s", gateway.Platform(), err, candidates[i+1].Platform())
+	}
+
+	return nil, fmt.Errorf("%w: nenhum gateway disponível", ErrUnknownGateway)
+}
+
+// weightedOrder sorteia a ordem das rotas sem reposição, proporcional ao peso
+func weightedOrder(routes []Route) []Route {
+	pool := append([]Route(nil), routes...)
+	ordered := make([]Route, 0, len(pool))
+
+	for len(pool) > 0 {
+		total := 0
+		for _, route := range pool {
+			total += route.Weight
+		}
+
+		pick := rand.Intn(total)
+		for i, route := range pool {
+			if pick < route.Weight {
+				ordered = append(ordered, route)
+				pool = append(pool[:i], pool[i+1:]...)
+				break
+			}
+			pick -= route.Weight
+		}
+	}
+	return ordered
+}
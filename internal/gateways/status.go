@@ -0,0 +1,61 @@
+package gateways
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+// statusTable traduz o vocabulário de status de um gateway para models.OrderStatus
+type statusTable map[string]models.OrderStatus
+
+func (t statusTable) normalize(platform, raw string) (models.OrderStatus, error) {
+	if status, ok := t[strings.ToLower(strings.TrimSpace(raw))]; ok {
+		return status, nil
+	}
+	return "", fmt.Errorf("%w: %s %q", ErrUnknownStatus, platform, raw)
+}
+
+// chargeStatus normaliza o status devolvido pela consulta da cobrança.
+// Com status desconhecido devolve o ChargeStatus (sem Status) junto com ErrUnknownStatus.
+func (t statusTable) chargeStatus(platform, transactionID, raw string) (*ChargeStatus, error) {
+	charge := &ChargeStatus{
+		TransactionID: transactionID,
+		RawStatus:     raw,
+	}
+
+	status, err := t.normalize(platform, raw)
+	if err != nil {
+		return charge, err
+	}
+
+	charge.Status = status
+	return charge, nil
+}
+
+// DetectWebhookGateway identifica o gateway de um payload recebido na rota
+// genérica /webhooks/payment, pelos campos que só o formato de cada um tem.
+func DetectWebhookGateway(body []byte) (string, bool) {
+	var probe struct {
+		Event       string          `json:"event"`
+		Type        string          `json:"type"`
+		ObjectID    json.RawMessage `json:"objectId"`
+		PaymentCode string          `json:"payment_code"`
+	}
+	if err := json.Unmarshal(body, &probe); err != nil {
+		return "", false
+	}
+
+	switch {
+	case strings.HasPrefix(probe.Event, "transaction."):
+		return "blupay", true
+	case probe.Type != "" && len(probe.ObjectID) > 0:
+		return "quantumpay", true
+	case probe.PaymentCode != "":
+		return "mangofy", true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/victtorkaiser/server-apis/internal/queue"
+)
+
+const (
+	deadLetterDefaultLimit = 20
+	deadLetterMaxLimit     = 500
+)
+
+type DeadLetterHandler struct {
+	bus queue.EventBus
+}
+
+func NewDeadLetterHandler(bus queue.EventBus) *DeadLetterHandler {
+	return &DeadLetterHandler{
+		bus: bus,
+	}
+}
+
+// Stats atende GET /api/v1/dead-letters
+func (h *DeadLetterHandler) Stats(c *gin.Context) {
+	stats, err := h.bus.DeadLetterStats()
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": stats})
+}
+
+// Peek atende GET /api/v1/dead-letters/:queue?limit=
+func (h *DeadLetterHandler) Peek(c *gin.Context) {
+	letters, err := h.bus.PeekDeadLetters(c.Param("queue"), deadLetterLimit(c))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": letters})
+}
+
+// Replay atende POST /api/v1/dead-letters/:queue/replay?limit=
+func (h *DeadLetterHandler) Replay(c *gin.Context) {
+	replayed, err := h.bus.ReplayDeadLetters(c.Request.Context(), c.Param("queue"), deadLetterLimit(c))
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error(), "replayed": replayed})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"replayed": replayed})
+}
+
+// Purge atende DELETE /api/v1/dead-letters/:queue
+func (h *DeadLetterHandler) Purge(c *gin.Context) {
+	purged, err := h.bus.PurgeDeadLetters(c.Param("queue"))
+	if err != nil {
+		h.fail(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purged": purged})
+}
+
+func (h *DeadLetterHandler) fail(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, queue.ErrUnknownQueue):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, queue.ErrNotConnected):
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	}
+}
+
+func deadLetterLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit <= 0 {
+		return deadLetterDefaultLimit
+	}
+	return min(limit, deadLetterMaxLimit)
+}
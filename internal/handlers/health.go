@@ -7,18 +7,21 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
+	"github.com/victtorkaiser/server-apis/internal/queue"
 	"gorm.io/gorm"
 )
 
 type HealthHandler struct {
 	db    *gorm.DB
 	redis *redis.Client
+	bus   queue.EventBus
 }
 
-func NewHealthHandler(db *gorm.DB, redis *redis.Client) *HealthHandler {
+func NewHealthHandler(db *gorm.DB, redis *redis.Client, bus queue.EventBus) *HealthHandler {
 	return &HealthHandler{
 		db:    db,
 		redis: redis,
+		bus:   bus,
 	}
 }
 
@@ -48,6 +51,19 @@ func (h *HealthHandler) Check(c *gin.Context) {
 		status["redis"] = "healthy"
 	}
 
+	// Check barramento de eventos: reconectando não derruba o serviço (eventos ficam no outbox)
+	busStatus := h.bus.Status()
+	status["event_bus"] = gin.H{
+		"name":       h.bus.Name(),
+		"state":      busStatus.State,
+		"since":      busStatus.Since,
+		"reconnects": busStatus.Reconnects,
+		"last_error": busStatus.LastError,
+	}
+	if busStatus.State != queue.StateConnected && status["status"] == "healthy" {
+		status["status"] = "degraded"
+	}
+
 	httpStatus := http.StatusOK
 	if status["status"] == "unhealthy" {
 		httpStatus = http.StatusServiceUnavailable
@@ -1,12 +1,17 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/qrcode"
 	"github.com/victtorkaiser/server-apis/internal/services"
+	"gorm.io/gorm"
 )
 
 type PaymentHandler struct {
@@ -24,15 +29,51 @@ func (h *PaymentHandler) Create(c *gin.Context) {
 		return
 	}
 
-	response, err := h.service.CreatePayment(c.Request.Context(), &req)
+	response, err := h.service.CreateDefaultPayment(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao criar pagamento", "details": err.Error()})
+		c.JSON(paymentErrorStatus(err), gin.H{"error": "Erro ao criar pagamento", "details": err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
+// CreateGatewayPayment atende POST /api/payment/:gateway
+func (h *PaymentHandler) CreateGatewayPayment(c *gin.Context) {
+	var req dto.GatewayPaymentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"success": false,
+			"message": "Dados inválidos: " + err.Error(),
+		})
+		return
+	}
+
+	resp, err := h.service.CreatePayment(c.Request.Context(), c.Param("gateway"), &req)
+	if err != nil {
+		c.JSON(paymentErrorStatus(err), gin.H{
+			"success": false,
+			"message": "Erro ao criar pagamento: " + err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gateways.ErrUnknownGateway):
+		return http.StatusNotFound
+	case errors.Is(err, gateways.ErrInvalidCharge):
+		return http.StatusBadRequest
+	case errors.Is(err, services.ErrInvalidPixCode):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func (h *PaymentHandler) GetByID(c *gin.Context) {
 	idParam := c.Param("id")
 	id, err := uuid.Parse(idParam)
@@ -50,6 +91,56 @@ func (h *PaymentHandler) GetByID(c *gin.Context) {
 	c.JSON(http.StatusOK, order)
 }
 
+// QRCodePNG atende GET /api/v1/payments/:id/qrcode.png
+func (h *PaymentHandler) QRCodePNG(c *gin.Context) {
+	h.renderQRCode(c, "png", "image/png")
+}
+
+// QRCodeSVG atende GET /api/v1/payments/:id/qrcode.svg
+func (h *PaymentHandler) QRCodeSVG(c *gin.Context) {
+	h.renderQRCode(c, "svg", "image/svg+xml")
+}
+
+// renderQRCode aceita ?size= (px) e ?margin= (módulos)
+func (h *PaymentHandler) renderQRCode(c *gin.Context, format, contentType string) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	opts := qrcode.Options{Size: qrcode.DefaultSize, Margin: qrcode.DefaultMargin}
+	if v := c.Query("size"); v != "" {
+		if opts.Size, err = strconv.Atoi(v); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "size inválido"})
+			return
+		}
+	}
+	if v := c.Query("margin"); v != "" {
+		if opts.Margin, err = strconv.Atoi(v); err != nil || opts.Margin < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "margin inválido"})
+			return
+		}
+	}
+
+	data, err := h.service.GetQRCode(c.Request.Context(), id, format, opts)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pedido não encontrado"})
+		return
+	case errors.Is(err, services.ErrNoPixCode):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pedido sem código PIX"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao gerar QR Code"})
+		return
+	}
+
+	// O código PIX do pedido não muda: a imagem pode ficar em cache
+	c.Header("Cache-Control", "private, max-age=3600")
+	c.Data(http.StatusOK, contentType, data)
+}
+
 func (h *PaymentHandler) GetByTransactionID(c *gin.Context) {
 	transactionID := c.Param("transaction_id")
 
@@ -77,6 +168,8 @@ func (h *PaymentHandler) GetByTransactionID(c *gin.Context) {
 		"valor":          order.Amount,
 		"platform":       order.Platform,
 		"pix_code":       order.PixCode,
+		"pix":            order.Pix,
+		"pix_issue":      order.PixIssue,
 		"created_at":     order.CreatedAt,
 		"updated_at":     order.UpdatedAt,
 		"approved_at":    order.ApprovedAt,
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/services"
+	"gorm.io/gorm"
+)
+
+type RefundHandler struct {
+	service *services.RefundService
+}
+
+func NewRefundHandler(service *services.RefundService) *RefundHandler {
+	return &RefundHandler{service: service}
+}
+
+// Create atende POST /api/v1/payments/:id/refund
+func (h *RefundHandler) Create(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// Body é opcional: sem ele o saldo inteiro é estornado
+	var req dto.RefundRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	refund, err := h.service.RefundOrder(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(refundErrorStatus(err), gin.H{"error": "Erro ao estornar pagamento", "details": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, refund)
+}
+
+// List atende GET /api/v1/payments/:id/refunds
+func (h *RefundHandler) List(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	refunds, err := h.service.ListRefunds(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar estornos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, refunds)
+}
+
+func refundErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, gateways.ErrUnknownGateway):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrRefundNotAllowed):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidRefundAmount):
+		return http.StatusBadRequest
+	default:
+		return http.StatusBadGateway
+	}
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/services"
+	"github.com/victtorkaiser/server-apis/internal/settlement"
+	"gorm.io/gorm"
+)
+
+type SettlementHandler struct {
+	service  *services.SettlementService
+	gateways *gateways.Registry
+}
+
+func NewSettlementHandler(service *services.SettlementService, registry *gateways.Registry) *SettlementHandler {
+	return &SettlementHandler{
+		service:  service,
+		gateways: registry,
+	}
+}
+
+// Import atende POST /api/v1/settlements (multipart: file, gateway, format, from, to)
+func (h *SettlementHandler) Import(c *gin.Context) {
+	gateway, ok := h.gateways.Get(c.PostForm("gateway"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Gateway não suportado"})
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Arquivo do extrato obrigatório (campo file)"})
+		return
+	}
+
+	format := settlement.Format(strings.ToLower(c.PostForm("format")))
+	if format == "" {
+		if format, err = settlement.FormatFromFilename(file.Filename); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	from, to, err := settlement.ParsePeriod(c.PostForm("from"), c.PostForm("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Erro ao ler arquivo"})
+		return
+	}
+	defer f.Close()
+
+	report, err := h.service.Import(c.Request.Context(), services.SettlementImport{
+		Gateway:  gateway.Name(),
+		Format:   format,
+		Filename: file.Filename,
+		From:     from,
+		To:       to,
+	}, f)
+	switch {
+	case errors.Is(err, services.ErrInvalidStatement):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao conciliar extrato"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// List atende GET /api/v1/settlements
+func (h *SettlementHandler) List(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve estar entre 1 e 500"})
+		return
+	}
+
+	reports, err := h.service.List(c.Request.Context(), c.Query("gateway"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar conciliações"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// Get atende GET /api/v1/settlements/:id (?kind=missing|extra|amount_mismatch|matched)
+func (h *SettlementHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	report, err := h.service.Get(c.Request.Context(), id, c.Query("kind"))
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conciliação não encontrada"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao buscar conciliação"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
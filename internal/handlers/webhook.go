@@ -1,42 +1,113 @@
 package handlers
 
 import (
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
-	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
 	"github.com/victtorkaiser/server-apis/internal/services"
+	"gorm.io/gorm"
 )
 
 type WebhookHandler struct {
-	webhookService *services.WebhookService
-	utmifyService  *services.UtmifyService
+	inboundService *services.InboundWebhookService
+	gateways       *gateways.Registry
 }
 
-func NewWebhookHandler(webhookService *services.WebhookService, utmifyService *services.UtmifyService) *WebhookHandler {
+func NewWebhookHandler(inboundService *services.InboundWebhookService, registry *gateways.Registry) *WebhookHandler {
 	return &WebhookHandler{
-		webhookService: webhookService,
-		utmifyService:  utmifyService,
+		inboundService: inboundService,
+		gateways:       registry,
 	}
 }
 
+// HandlePayment atende POST /webhooks/payment (formato genérico)
 func (h *WebhookHandler) HandlePayment(c *gin.Context) {
-	var webhook dto.WebhookPayload
-	if err := c.ShouldBindJSON(&webhook); err != nil {
-		log.Printf("❌ [Webhook] Payload inválido: %v", err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Payload inválido"})
+	h.receive(c, "payment", "Webhook")
+}
+
+// HandleGateway atende POST /webhooks/:gateway usando o parser do adapter
+func (h *WebhookHandler) HandleGateway(c *gin.Context) {
+	gateway, ok := h.gateways.Get(c.Param("gateway"))
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Gateway não suportado"})
 		return
 	}
 
-	log.Printf("📥 [Webhook] Recebido: PaymentCode=%s Status=%s", webhook.PaymentCode, webhook.PaymentStatus)
+	h.receive(c, gateway.Name(), gateway.Platform()+" Webhook")
+}
+
+// receive arquiva o body e os headers originais e só então processa o evento
+func (h *WebhookHandler) receive(c *gin.Context, gateway, logPrefix string) {
+	body, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		log.Printf("❌ [%s] Erro ao ler body: %v", logPrefix, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Payload inválido"})
+		return
+	}
 
-	// Processa o webhook
-	if err := h.webhookService.ProcessWebhook(c.Request.Context(), &webhook); err != nil {
-		log.Printf("❌ [Webhook] Erro ao processar: %v", err)
+	record, err := h.inboundService.Receive(c.Request.Context(), gateway, c.Request.Header, body)
+	switch {
+	case errors.Is(err, services.ErrInvalidWebhookPayload):
+		log.Printf("❌ [%s] Payload inválido: %v", logPrefix, err)
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Payload inválido"})
+		return
+	case errors.Is(err, gateways.ErrUnknownStatus):
+		// Arquivado como failed; pode ser reprocessado quando o status for mapeado
+		log.Printf("⚠️ [%s] %v", logPrefix, err)
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		log.Printf("❌ [%s] Erro ao processar: %v", logPrefix, err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao processar webhook"})
 		return
 	}
 
+	log.Printf("📥 [%s] Processado: event_id=%s transaction_id=%s", logPrefix, record.EventID, record.TransactionID)
+
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
+
+// ListInbound atende GET /api/v1/inbound-webhooks (admin)
+func (h *WebhookHandler) ListInbound(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit deve estar entre 1 e 500"})
+		return
+	}
+
+	records, err := h.inboundService.List(c.Request.Context(), c.Query("gateway"), c.Query("status"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, records)
+}
+
+// Reprocess atende POST /api/v1/inbound-webhooks/:id/reprocess (admin)
+func (h *WebhookHandler) Reprocess(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	// Com record != nil o erro é do processamento e já está gravado em record.Result
+	record, err := h.inboundService.Reprocess(c.Request.Context(), id)
+	switch {
+	case record == nil && errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook não encontrado"})
+		return
+	case record == nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao reprocessar webhook"})
+		return
+	}
+
+	c.JSON(http.StatusOK, record)
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/services"
+	"gorm.io/gorm"
+)
+
+type WebhookEndpointHandler struct {
+	service *services.WebhookEndpointService
+}
+
+func NewWebhookEndpointHandler(service *services.WebhookEndpointService) *WebhookEndpointHandler {
+	return &WebhookEndpointHandler{service: service}
+}
+
+// Create atende POST /api/v1/webhook-endpoints
+func (h *WebhookEndpointHandler) Create(c *gin.Context) {
+	var req dto.WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	endpoint, secret, err := h.service.Create(c.Request.Context(), req.URL, req.Events)
+	switch {
+	case errors.Is(err, services.ErrEndpointExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, services.ErrInvalidEndpointURL), errors.Is(err, services.ErrInvalidEvent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao cadastrar endpoint"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.WebhookEndpointResponse{WebhookEndpoint: endpoint, Secret: secret})
+}
+
+// List atende GET /api/v1/webhook-endpoints (sem os segredos)
+func (h *WebhookEndpointHandler) List(c *gin.Context) {
+	endpoints, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar endpoints"})
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// UpdateEvents atende PUT /api/v1/webhook-endpoints/:id/events
+func (h *WebhookEndpointHandler) UpdateEvents(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	var req dto.WebhookEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": err.Error()})
+		return
+	}
+
+	endpoint, err := h.service.UpdateEvents(c.Request.Context(), id, req.Events)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Endpoint não encontrado"})
+		return
+	case errors.Is(err, services.ErrInvalidEvent):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao atualizar eventos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoint)
+}
+
+// Events atende GET /api/v1/webhook-endpoints/events (catálogo de eventos)
+func (h *WebhookEndpointHandler) Events(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"events": events.Catalog})
+}
+
+// RotateSecret atende POST /api/v1/webhook-endpoints/:id/rotate-secret
+func (h *WebhookEndpointHandler) RotateSecret(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	endpoint, secret, err := h.service.RotateSecret(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Endpoint não encontrado"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao rotacionar segredo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.WebhookEndpointResponse{WebhookEndpoint: endpoint, Secret: secret})
+}
@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/services"
+	"gorm.io/gorm"
+)
+
+type WebhookLogHandler struct {
+	service *services.WebhookLogService
+}
+
+func NewWebhookLogHandler(service *services.WebhookLogService) *WebhookLogHandler {
+	return &WebhookLogHandler{service: service}
+}
+
+// List atende GET /api/v1/payments/:id/webhooks
+func (h *WebhookLogHandler) List(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pedido não encontrado"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao listar webhooks"})
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
+
+// Redeliver atende POST /api/v1/payments/:id/webhooks/redeliver
+func (h *WebhookLogHandler) Redeliver(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ID inválido"})
+		return
+	}
+
+	delivery, err := h.service.Redeliver(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pedido não encontrado"})
+		return
+	case errors.Is(err, services.ErrNoWebhookURL):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erro ao reenviar webhook"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, delivery)
+}
@@ -0,0 +1,31 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/victtorkaiser/server-apis/internal/config"
+)
+
+// AdminAuth protege as rotas administrativas com Authorization: Bearer <ADMIN_API_TOKEN>.
+// Sem token configurado as rotas ficam desabilitadas.
+func AdminAuth(cfg *config.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if cfg.AdminAPIToken == "" {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "API administrativa desabilitada (ADMIN_API_TOKEN vazio)"})
+			return
+		}
+
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(token), []byte(cfg.AdminAPIToken)) != 1 {
+			log.Printf("🚫 [Admin] Acesso negado de %s em %s", c.ClientIP(), c.Request.URL.Path)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Não autorizado"})
+			return
+		}
+
+		c.Next()
+	}
+}
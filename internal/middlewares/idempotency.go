@@ -0,0 +1,165 @@
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	IdempotencyHeader = "Idempotency-Key"
+	// Tempo máximo que uma requisição fica "em andamento": o failover pode passar pelos
+	// 4 gateways com timeout de 30s cada, mais o banco; a margem cobre o pior caso
+	idempotencyLockTTL = 5 * time.Minute
+)
+
+// releaseLock apaga o lock só se ele ainda pertence a esta requisição
+var releaseLock = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+type idempotentResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+	RequestHash string `json:"request_hash"`
+}
+
+// responseRecorder captura o corpo da resposta enquanto ela é escrita
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+// Idempotency armazena a primeira resposta para cada Idempotency-Key e a repete em retries.
+// Requisições duplicadas ainda em andamento recebem 409.
+func Idempotency(client *redis.Client, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyHeader)
+		if key == "" || client == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"success": false, "message": "Erro ao ler body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		sum := sha256.Sum256(body)
+		requestHash := hex.EncodeToString(sum[:])
+
+		// Não cancela junto com a requisição: a resposta precisa ser gravada mesmo se o cliente cair
+		ctx := context.WithoutCancel(c.Request.Context())
+		// O path concreto separa a mesma key usada em recursos diferentes (ex: /payments/A/refund e /payments/B/refund)
+		scope := c.Request.Method + ":" + c.Request.URL.Path + ":" + key
+		responseKey := "idempotency:response:" + scope
+		lockKey := "idempotency:lock:" + scope
+
+		// Já existe resposta armazenada: repete
+		replayed, err := replayStored(ctx, c, client, responseKey, requestHash, key)
+		if err != nil {
+			// Redis indisponível: segue sem idempotência para não derrubar o checkout
+			log.Printf("⚠️ [Idempotency] Erro no Redis: %v (seguindo sem idempotência)", err)
+			c.Next()
+			return
+		}
+		if replayed {
+			return
+		}
+
+		// Marca a requisição como em andamento; o token identifica o dono do lock
+		lockToken := requestHash + ":" + uuid.New().String()
+		acquired, err := client.SetNX(ctx, lockKey, lockToken, idempotencyLockTTL).Result()
+		if err != nil {
+			log.Printf("⚠️ [Idempotency] Erro no Redis: %v (seguindo sem idempotência)", err)
+			c.Next()
+			return
+		}
+		if !acquired {
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{
+				"success": false,
+				"message": "Requisição com esta Idempotency-Key ainda em processamento",
+			})
+			return
+		}
+		defer releaseLock.Run(ctx, client, []string{lockKey}, lockToken)
+
+		// A requisição anterior pode ter terminado entre o GET e o SETNX
+		if replayed, err := replayStored(ctx, c, client, responseKey, requestHash, key); err == nil && replayed {
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+
+		c.Next()
+
+		// Erros 5xx não são armazenados para que o cliente possa tentar de novo
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		data, _ := json.Marshal(idempotentResponse{
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			RequestHash: requestHash,
+		})
+		if err := client.Set(ctx, responseKey, data, ttl).Err(); err != nil {
+			log.Printf("⚠️ [Idempotency] Erro ao armazenar resposta key=%s: %v", key, err)
+		}
+	}
+}
+
+// replayStored repete a resposta armazenada, se houver. Devolve true quando a requisição
+// já foi respondida (replay ou 422 por payload diferente).
+func replayStored(ctx context.Context, c *gin.Context, client *redis.Client, responseKey, requestHash, key string) (bool, error) {
+	data, err := client.Get(ctx, responseKey).Bytes()
+	if err == redis.Nil {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var stored idempotentResponse
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return false, nil
+	}
+
+	if stored.RequestHash != requestHash {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{
+			"success": false,
+			"message": "Idempotency-Key já utilizada com outro payload",
+		})
+		return true, nil
+	}
+
+	log.Printf("🔁 [Idempotency] Repetindo resposta para key=%s", key)
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(stored.Status, stored.ContentType, stored.Body)
+	c.Abort()
+	return true, nil
+}
@@ -0,0 +1,178 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/victtorkaiser/server-apis/internal/config"
+)
+
+// webhookMaxBodyBytes limita o body lido dos webhooks recebidos
+const webhookMaxBodyBytes = 1 << 20
+
+// WebhookSignature valida a autenticidade dos webhooks recebidos antes do processamento.
+// O gateway é lido do parâmetro :gateway da rota; sem ele usa defaultGateway.
+// Gateway sem configuração é recusado; só passa sem verificação com <GATEWAY>_WEBHOOK_VERIFY=false.
+// Com tolerância de timestamp configurada, modos que não assinam o timestamp (hmac-body e secret)
+// são recusados: sem ele o webhook pode ser reenviado indefinidamente.
+func WebhookSignature(cfg *config.Config, defaultGateway string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gateway := strings.ToLower(c.Param("gateway"))
+		if gateway == "" {
+			gateway = defaultGateway
+		}
+
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, webhookMaxBodyBytes)
+
+		auth, ok := cfg.WebhookAuth[gateway]
+		if !ok {
+			rejectWebhook(c, gateway, "gateway sem configuração de verificação")
+			return
+		}
+		if !auth.Enabled {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				log.Printf("🚫 [Webhook] Rejeitado (%s) de %s: body acima de %d bytes", gateway, c.ClientIP(), webhookMaxBodyBytes)
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "Body do webhook muito grande"})
+				return
+			}
+			rejectWebhook(c, gateway, "erro ao ler body")
+			return
+		}
+		// Devolve o body para o handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if auth.Secret == "" {
+			rejectWebhook(c, gateway, "verificação habilitada sem segredo configurado")
+			return
+		}
+
+		signature := c.GetHeader(auth.SignatureHeader)
+		if signature == "" {
+			rejectWebhook(c, gateway, "header "+auth.SignatureHeader+" ausente")
+			return
+		}
+
+		tolerance := time.Duration(auth.TimestampTolerance) * time.Second
+		timestamp := c.GetHeader(auth.TimestampHeader)
+		if tolerance > 0 {
+			if !signsTimestamp(auth.Mode) {
+				rejectWebhook(c, gateway, "modo "+auth.Mode+" não assina o timestamp (sem proteção contra replay)")
+				return
+			}
+			if err := checkTimestamp(timestamp, tolerance); err != "" {
+				rejectWebhook(c, gateway, err)
+				return
+			}
+		}
+
+		switch auth.Mode {
+		case "secret":
+			if subtle.ConstantTimeCompare([]byte(signature), []byte(auth.Secret)) != 1 {
+				rejectWebhook(c, gateway, "segredo inválido")
+				return
+			}
+		case "hmac-body":
+			if !validBodyHMAC(auth.Secret, body, signature) {
+				rejectWebhook(c, gateway, "assinatura HMAC inválida")
+				return
+			}
+		default:
+			if !validHMAC(auth.Secret, timestamp, body, signature) {
+				rejectWebhook(c, gateway, "assinatura HMAC inválida")
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// signsTimestamp indica se o modo assina o timestamp junto com o body
+func signsTimestamp(mode string) bool {
+	return mode != "hmac-body" && mode != "secret"
+}
+
+// validHMAC compara a assinatura recebida com HMAC-SHA256(secret, timestamp + "." + body)
+func validHMAC(secret, timestamp string, body []byte, signature string) bool {
+	return equalHMAC(secret, signature, []byte(timestamp), []byte("."), body)
+}
+
+// validBodyHMAC compara a assinatura recebida com HMAC-SHA256(secret, body) (formato da BluPay)
+func validBodyHMAC(secret string, body []byte, signature string) bool {
+	return equalHMAC(secret, signature, body)
+}
+
+func equalHMAC(secret, signature string, parts ...[]byte) bool {
+	signature = strings.TrimPrefix(strings.TrimSpace(signature), "sha256=")
+	received, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, part := range parts {
+		mac.Write(part)
+	}
+
+	return hmac.Equal(received, mac.Sum(nil))
+}
+
+// LogWebhookAuth avisa na subida quais rotas de webhook aceitam chamadas sem verificação
+// ou vão recusar tudo por falta de segredo
+func LogWebhookAuth(cfg *config.Config) {
+	for gateway, auth := range cfg.WebhookAuth {
+		switch {
+		case !auth.Enabled:
+			log.Printf("🚨 [Webhook] Verificação DESLIGADA para %s: qualquer um pode enviar webhooks para essa rota", gateway)
+		case auth.Secret == "":
+			log.Printf("⚠️ [Webhook] %s sem segredo configurado: webhooks serão recusados (defina %s_WEBHOOK_SECRET)", gateway, strings.ToUpper(gateway))
+		case auth.TimestampTolerance <= 0:
+			log.Printf("🚨 [Webhook] %s sem checagem de timestamp: webhooks repetidos são aceitos a qualquer tempo", gateway)
+		case !signsTimestamp(auth.Mode):
+			log.Printf("⚠️ [Webhook] %s no modo %s não assina o timestamp: webhooks serão recusados (use hmac ou %s_WEBHOOK_TIMESTAMP_TOLERANCE=0)", gateway, auth.Mode, strings.ToUpper(gateway))
+		}
+	}
+}
+
+// checkTimestamp rejeita timestamps fora da tolerância (proteção contra replay)
+func checkTimestamp(timestamp string, tolerance time.Duration) string {
+	if timestamp == "" {
+		return "timestamp ausente"
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return "timestamp inválido"
+	}
+
+	diff := time.Since(time.Unix(unix, 0))
+	if time.Duration(math.Abs(float64(diff))) > tolerance {
+		return "timestamp fora da tolerância"
+	}
+
+	return ""
+}
+
+func rejectWebhook(c *gin.Context, gateway, reason string) {
+	log.Printf("🚫 [Webhook] Rejeitado (%s) de %s: %s", gateway, c.ClientIP(), reason)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Assinatura do webhook inválida"})
+}
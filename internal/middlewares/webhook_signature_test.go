@@ -0,0 +1,198 @@
+package middlewares
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/victtorkaiser/server-apis/internal/config"
+)
+
+func sign(secret string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, part := range parts {
+		mac.Write([]byte(part))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebhookSignature(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const secret = "segredo"
+	body := `{"event":"transaction.paid","objectId":"abc"}`
+	now := strconv.FormatInt(time.Now().Unix(), 10)
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+
+	auth := func(mode string, tolerance int) config.WebhookAuthConfig {
+		return config.WebhookAuthConfig{
+			Enabled:            true,
+			Mode:               mode,
+			Secret:             secret,
+			SignatureHeader:    "X-Webhook-Signature",
+			TimestampHeader:    "X-Webhook-Timestamp",
+			TimestampTolerance: tolerance,
+		}
+	}
+
+	tests := []struct {
+		name    string
+		gateway string
+		auth    config.WebhookAuthConfig
+		headers map[string]string
+		body    string
+		want    int
+	}{
+		{
+			name:    "hmac-body válido sem timestamp (BluPay)",
+			gateway: "blupay",
+			auth:    auth("hmac-body", 0),
+			headers: map[string]string{"X-Webhook-Signature": sign(secret, body)},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "hmac-body com prefixo sha256=",
+			gateway: "blupay",
+			auth:    auth("hmac-body", 0),
+			headers: map[string]string{"X-Webhook-Signature": "sha256=" + sign(secret, body)},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "hmac-body recusado com tolerância de timestamp",
+			gateway: "blupay",
+			auth:    auth("hmac-body", 300),
+			headers: map[string]string{"X-Webhook-Signature": sign(secret, body), "X-Webhook-Timestamp": now},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "hmac-body sem tolerância ignora timestamp antigo",
+			gateway: "blupay",
+			auth:    auth("hmac-body", 0),
+			headers: map[string]string{"X-Webhook-Signature": sign(secret, body), "X-Webhook-Timestamp": old},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "hmac-body com assinatura de outro segredo",
+			gateway: "blupay",
+			auth:    auth("hmac-body", 0),
+			headers: map[string]string{"X-Webhook-Signature": sign("outro", body)},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "hmac-body sem header de assinatura",
+			gateway: "blupay",
+			auth:    auth("hmac-body", 0),
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "hmac com timestamp válido",
+			gateway: "quantumpay",
+			auth:    auth("hmac", 300),
+			headers: map[string]string{"X-Webhook-Signature": sign(secret, now, ".", body), "X-Webhook-Timestamp": now},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "hmac sem timestamp",
+			gateway: "quantumpay",
+			auth:    auth("hmac", 300),
+			headers: map[string]string{"X-Webhook-Signature": sign(secret, body)},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "hmac com timestamp fora da tolerância",
+			gateway: "quantumpay",
+			auth:    auth("hmac", 300),
+			headers: map[string]string{"X-Webhook-Signature": sign(secret, old, ".", body), "X-Webhook-Timestamp": old},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "hmac sem tolerância aceita timestamp antigo",
+			gateway: "quantumpay",
+			auth:    auth("hmac", 0),
+			headers: map[string]string{"X-Webhook-Signature": sign(secret, old, ".", body), "X-Webhook-Timestamp": old},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "secret recusado com tolerância de timestamp",
+			gateway: "mangofy",
+			auth:    auth("secret", 300),
+			headers: map[string]string{"X-Webhook-Signature": secret, "X-Webhook-Timestamp": now},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "secret correto",
+			gateway: "mangofy",
+			auth:    auth("secret", 0),
+			headers: map[string]string{"X-Webhook-Signature": secret},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "habilitado sem segredo recusa",
+			gateway: "genesys",
+			auth:    config.WebhookAuthConfig{Enabled: true, Mode: "hmac", SignatureHeader: "X-Webhook-Signature"},
+			headers: map[string]string{"X-Webhook-Signature": sign("", body)},
+			want:    http.StatusUnauthorized,
+		},
+		{
+			name:    "verificação desligada explicitamente",
+			gateway: "genesys",
+			auth:    config.WebhookAuthConfig{Enabled: false},
+			want:    http.StatusOK,
+		},
+		{
+			name:    "body acima do limite",
+			gateway: "quantumpay",
+			auth:    auth("hmac", 300),
+			body:    strings.Repeat("x", webhookMaxBodyBytes+1),
+			want:    http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:    "gateway sem configuração recusa",
+			gateway: "desconhecido",
+			want:    http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &config.Config{WebhookAuth: map[string]config.WebhookAuthConfig{}}
+			if tt.gateway != "desconhecido" {
+				cfg.WebhookAuth[tt.gateway] = tt.auth
+			}
+
+			var received string
+			r := gin.New()
+			r.POST("/webhooks/:gateway", WebhookSignature(cfg, ""), func(c *gin.Context) {
+				b, _ := io.ReadAll(c.Request.Body)
+				received = string(b)
+				c.Status(http.StatusOK)
+			})
+
+			payload := body
+			if tt.body != "" {
+				payload = tt.body
+			}
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/"+tt.gateway, strings.NewReader(payload))
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, quer %d", w.Code, tt.want)
+			}
+			if tt.want == http.StatusOK && received != body {
+				t.Fatalf("handler recebeu body %q, quer %q", received, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type BusMessageStatus string
+
+const (
+	BusMessagePending BusMessageStatus = "pending"
+	// BusMessageDead é a .dlq do barramento no Postgres
+	BusMessageDead BusMessageStatus = "dead"
+)
+
+// BusMessage é uma mensagem do barramento de eventos no Postgres (EVENT_BUS=postgres ou
+// fallback sem RabbitMQ). Mensagens processadas são apagadas; as que esgotam as
+// tentativas ficam como dead até serem reprocessadas ou apagadas.
+type BusMessage struct {
+	ID          uuid.UUID        `gorm:"type:uuid;primary_key" json:"id"`
+	Queue       string           `gorm:"type:varchar(100);not null;index:idx_bus_messages_ready,priority:1" json:"queue"`
+	Payload     string           `gorm:"type:jsonb;not null" json:"payload"`
+	Status      BusMessageStatus `gorm:"type:varchar(20);not null;index:idx_bus_messages_ready,priority:2" json:"status"`
+	Attempts    int              `gorm:"not null;default:0" json:"attempts"`
+	LastError   string           `gorm:"type:text" json:"last_error,omitempty"`
+	AvailableAt time.Time        `gorm:"not null;index:idx_bus_messages_ready,priority:3" json:"available_at"`
+	FailedAt    *time.Time       `json:"failed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (BusMessage) TableName() string {
+	return "bus_messages"
+}
+
+func (m *BusMessage) BeforeCreate(tx *gorm.DB) error {
+	if m.ID == uuid.Nil {
+		m.ID = uuid.New()
+	}
+	if m.Status == "" {
+		m.Status = BusMessagePending
+	}
+	if m.AvailableAt.IsZero() {
+		m.AvailableAt = time.Now()
+	}
+	return nil
+}
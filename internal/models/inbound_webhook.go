@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type InboundWebhookStatus string
+
+const (
+	InboundWebhookStatusReceived  InboundWebhookStatus = "received"
+	InboundWebhookStatusProcessed InboundWebhookStatus = "processed"
+	InboundWebhookStatusFailed    InboundWebhookStatus = "failed"
+)
+
+// InboundWebhook guarda o callback do gateway exatamente como chegou,
+// para reprocessar depois de uma correção no parser ou no processamento.
+type InboundWebhook struct {
+	ID             uuid.UUID            `gorm:"type:uuid;primary_key" json:"id"`
+	Gateway        string               `gorm:"type:varchar(50);not null;uniqueIndex:idx_inbound_webhook_event,priority:1" json:"gateway"`
+	EventID        string               `gorm:"type:varchar(255);not null;uniqueIndex:idx_inbound_webhook_event,priority:2" json:"event_id"` // id do evento no gateway ou sha256 do body
+	TransactionID  string               `gorm:"type:varchar(255);index" json:"transaction_id,omitempty"`
+	Headers        string               `gorm:"type:jsonb" json:"headers"`
+	Body           string               `gorm:"type:text;not null" json:"body"`
+	Status         InboundWebhookStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	Result         string               `gorm:"type:text" json:"result,omitempty"` // resultado do último processamento
+	Attempts       int                  `gorm:"not null;default:0" json:"attempts"`
+	DuplicateCount int                  `gorm:"not null;default:0" json:"duplicate_count"`
+	ReceivedAt     time.Time            `gorm:"not null;index" json:"received_at"`
+	ProcessedAt    *time.Time           `json:"processed_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (w *InboundWebhook) BeforeCreate(tx *gorm.DB) error {
+	if w.ID == uuid.Nil {
+		w.ID = uuid.New()
+	}
+	if w.Status == "" {
+		w.Status = InboundWebhookStatusReceived
+	}
+	if w.ReceivedAt.IsZero() {
+		w.ReceivedAt = time.Now()
+	}
+	return nil
+}
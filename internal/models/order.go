@@ -4,29 +4,44 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/pix"
 	"gorm.io/gorm"
 )
 
 type OrderStatus string
 
 const (
-	OrderStatusPending        OrderStatus = "pending"
-	OrderStatusWaitingPayment OrderStatus = "waiting_payment"
-	OrderStatusPaid           OrderStatus = "paid"
-	OrderStatusApproved       OrderStatus = "approved"
-	OrderStatusRefunded       OrderStatus = "refunded"
-	OrderStatusCancelled      OrderStatus = "cancelled"
+	OrderStatusPending           OrderStatus = "pending"
+	OrderStatusWaitingPayment    OrderStatus = "waiting_payment"
+	OrderStatusPaid              OrderStatus = "paid"
+	OrderStatusApproved          OrderStatus = "approved"
+	OrderStatusRefunded          OrderStatus = "refunded"
+	OrderStatusPartiallyRefunded OrderStatus = "partially_refunded"
+	OrderStatusCancelled         OrderStatus = "cancelled"
+	OrderStatusExpired           OrderStatus = "expired"
+	OrderStatusNeedsReview       OrderStatus = "needs_review" // pagamento com valor/moeda divergente
 )
 
 type Order struct {
-	ID            uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
-	TransactionID string     `gorm:"uniqueIndex;not null" json:"transaction_id"`
-	Status        OrderStatus `gorm:"type:varchar(50);not null" json:"status"`
-	Amount        int        `gorm:"not null" json:"amount"` // em centavos
-	PaymentMethod string     `gorm:"type:varchar(50)" json:"payment_method"`
-	Platform      string     `gorm:"type:varchar(100)" json:"platform"`
-	PixCode       string     `gorm:"type:text" json:"pix_code,omitempty"`
-	WebhookURL    string     `gorm:"type:text" json:"webhook_url,omitempty"`
+	ID             uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	TransactionID  string      `gorm:"uniqueIndex;not null" json:"transaction_id"`
+	ExternalRef    string      `gorm:"type:varchar(255);index" json:"external_ref,omitempty"`
+	Status         OrderStatus `gorm:"type:varchar(50);not null" json:"status"`
+	Amount         int         `gorm:"not null" json:"amount"`                    // em centavos
+	RefundedAmount int         `gorm:"not null;default:0" json:"refunded_amount"` // em centavos
+	PaymentMethod  string      `gorm:"type:varchar(50)" json:"payment_method"`
+	Platform       string      `gorm:"type:varchar(100)" json:"platform"`
+	Gateway        string      `gorm:"type:varchar(50);index" json:"gateway"`              // gateway que criou a cobrança
+	FailedGateways string      `gorm:"type:varchar(255)" json:"failed_gateways,omitempty"` // gateways que falharam antes (failover)
+	PixCode        string      `gorm:"type:text" json:"pix_code,omitempty"`
+	PixIssue       string      `gorm:"type:varchar(255)" json:"pix_issue,omitempty"` // problema encontrado no BR Code (CRC, valor)
+	WebhookURL     string      `gorm:"type:text" json:"webhook_url,omitempty"`
+
+	// Tarifa do gateway (criação da cobrança, atualizada pelos webhooks)
+	GatewayFee          int     `gorm:"not null;default:0" json:"gateway_fee"`           // em centavos
+	FeeFixedAmount      int     `gorm:"not null;default:0" json:"fee_fixed_amount"`      // parte fixa, em centavos
+	FeeSpreadPercentage float64 `gorm:"not null;default:0" json:"fee_spread_percentage"` // parte percentual
+	NetAmount           int     `gorm:"not null;default:0" json:"net_amount"`            // líquido para o lojista, em centavos
 
 	CustomerID uuid.UUID `gorm:"type:uuid" json:"customer_id"`
 	Customer   Customer  `gorm:"foreignKey:CustomerID" json:"customer"`
@@ -36,9 +51,18 @@ type Order struct {
 	TrackingParameterID *uuid.UUID         `gorm:"type:uuid" json:"tracking_parameter_id,omitempty"`
 	TrackingParameter   *TrackingParameter `gorm:"foreignKey:TrackingParameterID" json:"tracking_parameters,omitempty"`
 
+	// Campos do BR Code, preenchidos na leitura (não persistidos)
+	Pix *pix.BRCode `gorm:"-" json:"pix,omitempty"`
+
+	StatusHistory []OrderStatusHistory `gorm:"foreignKey:OrderID" json:"status_history,omitempty"`
+
+	ExpiresAt  *time.Time `gorm:"index" json:"expires_at,omitempty"`
 	ApprovedAt *time.Time `json:"approved_at,omitempty"`
 	RefundedAt *time.Time `json:"refunded_at,omitempty"`
 
+	// Última consulta de status no gateway pelo StatusPoller
+	StatusPolledAt *time.Time `gorm:"index" json:"status_polled_at,omitempty"`
+
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
@@ -51,6 +75,23 @@ func (o *Order) BeforeCreate(tx *gorm.DB) error {
 	return nil
 }
 
+// UserCommission é o valor líquido do lojista: o informado pelo gateway ou
+// o valor do pedido menos a tarifa.
+func (o *Order) UserCommission() int {
+	if o.NetAmount > 0 {
+		return o.NetAmount
+	}
+	return o.Amount - o.GatewayFee
+}
+
+// AfterFind preenche Pix a partir do PixCode gravado
+func (o *Order) AfterFind(tx *gorm.DB) error {
+	if o.PixCode != "" {
+		o.Pix, _ = pix.Parse(o.PixCode)
+	}
+	return nil
+}
+
 type Customer struct {
 	ID       uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
 	Name     string    `gorm:"type:varchar(255);not null" json:"name"`
@@ -73,13 +114,13 @@ func (c *Customer) BeforeCreate(tx *gorm.DB) error {
 }
 
 type Product struct {
-	ID        uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
-	Code      string    `gorm:"type:varchar(100);not null" json:"code"`
-	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
-	PlanID    string    `gorm:"type:varchar(100)" json:"plan_id,omitempty"`
-	PlanName  string    `gorm:"type:varchar(255)" json:"plan_name,omitempty"`
-	Quantity  int       `gorm:"not null;default:1" json:"quantity"`
-	Price     int       `gorm:"not null" json:"price"` // em centavos
+	ID       uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	Code     string    `gorm:"type:varchar(100);not null" json:"code"`
+	Name     string    `gorm:"type:varchar(255);not null" json:"name"`
+	PlanID   string    `gorm:"type:varchar(100)" json:"plan_id,omitempty"`
+	PlanName string    `gorm:"type:varchar(255)" json:"plan_name,omitempty"`
+	Quantity int       `gorm:"not null;default:1" json:"quantity"`
+	Price    int       `gorm:"not null" json:"price"` // em centavos
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
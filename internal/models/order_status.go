@@ -0,0 +1,95 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidTransition indica uma mudança de status não permitida (ex: approved -> pending)
+	ErrInvalidTransition = errors.New("transição de status inválida")
+	// ErrSameStatus indica que o pedido já está no status informado
+	ErrSameStatus = errors.New("pedido já está no status informado")
+)
+
+// orderTransitions lista as transições permitidas a partir de cada status
+var orderTransitions = map[OrderStatus][]OrderStatus{
+	OrderStatusPending:           {OrderStatusWaitingPayment, OrderStatusApproved, OrderStatusPaid, OrderStatusCancelled, OrderStatusExpired, OrderStatusNeedsReview},
+	OrderStatusWaitingPayment:    {OrderStatusApproved, OrderStatusPaid, OrderStatusCancelled, OrderStatusExpired, OrderStatusNeedsReview},
+	OrderStatusExpired:           {OrderStatusApproved, OrderStatusPaid, OrderStatusNeedsReview}, // pagamento confirmado depois do vencimento local
+	OrderStatusNeedsReview:       {OrderStatusApproved, OrderStatusPaid, OrderStatusCancelled, OrderStatusRefunded},
+	OrderStatusApproved:          {OrderStatusPartiallyRefunded, OrderStatusRefunded},
+	OrderStatusPaid:              {OrderStatusPartiallyRefunded, OrderStatusRefunded},
+	OrderStatusPartiallyRefunded: {OrderStatusRefunded},
+	OrderStatusRefunded:          {},
+	OrderStatusCancelled:         {},
+}
+
+// IsPaid indica se o status representa pagamento confirmado (paid e approved são equivalentes)
+func (s OrderStatus) IsPaid() bool {
+	return s == OrderStatusApproved || s == OrderStatusPaid
+}
+
+// IsFinal indica se o status não aceita mais nenhuma transição
+func (s OrderStatus) IsFinal() bool {
+	return len(orderTransitions[s]) == 0
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range orderTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// TransitionTo muda o status do pedido respeitando a máquina de estados
+// e preenche ApprovedAt/RefundedAt apenas na primeira vez.
+func (o *Order) TransitionTo(next OrderStatus) error {
+	if o.Status == next || (o.Status.IsPaid() && next.IsPaid()) {
+		return ErrSameStatus
+	}
+
+	if !o.Status.CanTransitionTo(next) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, o.Status, next)
+	}
+
+	now := time.Now()
+	if next.IsPaid() && o.ApprovedAt == nil {
+		o.ApprovedAt = &now
+	}
+	if (next == OrderStatusRefunded || next == OrderStatusPartiallyRefunded) && o.RefundedAt == nil {
+		o.RefundedAt = &now
+	}
+
+	o.Status = next
+	return nil
+}
+
+// OrderStatusHistory registra cada mudança de status de um pedido
+type OrderStatusHistory struct {
+	ID         uuid.UUID   `gorm:"type:uuid;primary_key" json:"id"`
+	OrderID    uuid.UUID   `gorm:"type:uuid;not null;index" json:"order_id"`
+	FromStatus OrderStatus `gorm:"type:varchar(50)" json:"from_status"`
+	ToStatus   OrderStatus `gorm:"type:varchar(50);not null" json:"to_status"`
+	Source     string      `gorm:"type:varchar(100);not null" json:"source"` // ex: webhook:blupay, api
+	RawStatus  string      `gorm:"type:varchar(100)" json:"raw_status,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+func (h *OrderStatusHistory) BeforeCreate(tx *gorm.DB) error {
+	if h.ID == uuid.Nil {
+		h.ID = uuid.New()
+	}
+	return nil
+}
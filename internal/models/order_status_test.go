@@ -0,0 +1,67 @@
+package models
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOrderTransitionTo(t *testing.T) {
+	tests := []struct {
+		name         string
+		from         OrderStatus
+		to           OrderStatus
+		wantErr      error
+		wantApproved bool
+		wantRefunded bool
+	}{
+		{"pending -> waiting_payment", OrderStatusPending, OrderStatusWaitingPayment, nil, false, false},
+		{"pending -> paid", OrderStatusPending, OrderStatusPaid, nil, true, false},
+		{"waiting_payment -> approved", OrderStatusWaitingPayment, OrderStatusApproved, nil, true, false},
+		{"approved -> refunded", OrderStatusApproved, OrderStatusRefunded, nil, false, true},
+		{"waiting_payment -> expired", OrderStatusWaitingPayment, OrderStatusExpired, nil, false, false},
+		{"expired -> paid (pago após o vencimento)", OrderStatusExpired, OrderStatusPaid, nil, true, false},
+		{"needs_review -> refunded", OrderStatusNeedsReview, OrderStatusRefunded, nil, false, true},
+		{"approved -> partially_refunded", OrderStatusApproved, OrderStatusPartiallyRefunded, nil, false, true},
+		{"partially_refunded -> refunded", OrderStatusPartiallyRefunded, OrderStatusRefunded, nil, false, true},
+		{"mesmo status", OrderStatusPending, OrderStatusPending, ErrSameStatus, false, false},
+		{"approved -> paid é o mesmo status", OrderStatusApproved, OrderStatusPaid, ErrSameStatus, false, false},
+		{"approved -> pending", OrderStatusApproved, OrderStatusPending, ErrInvalidTransition, false, false},
+		{"expired -> cancelled", OrderStatusExpired, OrderStatusCancelled, ErrInvalidTransition, false, false},
+		{"refunded é final", OrderStatusRefunded, OrderStatusPaid, ErrInvalidTransition, false, false},
+		{"cancelled é final", OrderStatusCancelled, OrderStatusApproved, ErrInvalidTransition, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &Order{Status: tt.from}
+
+			err := order.TransitionTo(tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TransitionTo(%s -> %s) = %v, esperado %v", tt.from, tt.to, err, tt.wantErr)
+			}
+
+			want := tt.to
+			if tt.wantErr != nil {
+				want = tt.from
+			}
+			if order.Status != want {
+				t.Errorf("status = %s, esperado %s", order.Status, want)
+			}
+			if (order.ApprovedAt != nil) != tt.wantApproved {
+				t.Errorf("ApprovedAt preenchido = %v, esperado %v", order.ApprovedAt != nil, tt.wantApproved)
+			}
+			if (order.RefundedAt != nil) != tt.wantRefunded {
+				t.Errorf("RefundedAt preenchido = %v, esperado %v", order.RefundedAt != nil, tt.wantRefunded)
+			}
+		})
+	}
+}
+
+func TestOrderStatusIsFinal(t *testing.T) {
+	for status := range orderTransitions {
+		want := status == OrderStatusRefunded || status == OrderStatusCancelled
+		if status.IsFinal() != want {
+			t.Errorf("%s.IsFinal() = %v, esperado %v", status, status.IsFinal(), want)
+		}
+	}
+}
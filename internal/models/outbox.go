@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusPublished OutboxStatus = "published"
+	// OutboxStatusFailed marca eventos sem fila de destino no broker; não são retentados
+	OutboxStatusFailed OutboxStatus = "failed"
+)
+
+// OutboxEvent é um evento gravado na mesma transação da alteração que o originou.
+// O OutboxRelay publica no RabbitMQ e marca como publicado (entrega at-least-once).
+type OutboxEvent struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primary_key" json:"id"`
+	Queue       string       `gorm:"type:varchar(100);not null;index" json:"queue"`
+	EventID     string       `gorm:"type:varchar(36);index" json:"event_id,omitempty"`
+	Payload     string       `gorm:"type:jsonb;not null" json:"payload"`
+	Status      OutboxStatus `gorm:"type:varchar(20);not null;index:idx_outbox_pending,priority:1" json:"status"`
+	Attempts    int          `gorm:"not null;default:0" json:"attempts"`
+	LastError   string       `gorm:"type:text" json:"last_error,omitempty"`
+	AvailableAt time.Time    `gorm:"not null;index:idx_outbox_pending,priority:2" json:"available_at"`
+	PublishedAt *time.Time   `json:"published_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+func (e *OutboxEvent) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	if e.Status == "" {
+		e.Status = OutboxStatusPending
+	}
+	if e.AvailableAt.IsZero() {
+		e.AvailableAt = time.Now()
+	}
+	return nil
+}
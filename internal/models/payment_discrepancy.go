@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrderCurrency é a moeda de todos os pedidos (cobranças PIX)
+const OrderCurrency = "BRL"
+
+type DiscrepancyKind string
+
+const (
+	DiscrepancyUnderpayment     DiscrepancyKind = "underpayment"
+	DiscrepancyOverpayment      DiscrepancyKind = "overpayment"
+	DiscrepancyCurrencyMismatch DiscrepancyKind = "currency_mismatch"
+)
+
+// PaymentDiscrepancy registra um pagamento confirmado pelo gateway com valor ou
+// moeda diferente do pedido. O pedido fica em needs_review em vez de aprovado.
+type PaymentDiscrepancy struct {
+	ID               uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	OrderID          uuid.UUID       `gorm:"type:uuid;not null;index" json:"order_id"`
+	Kind             DiscrepancyKind `gorm:"type:varchar(30);not null" json:"kind"`
+	ExpectedAmount   int             `gorm:"not null" json:"expected_amount"` // em centavos
+	ReceivedAmount   int             `gorm:"not null" json:"received_amount"` // em centavos
+	ExpectedCurrency string          `gorm:"type:varchar(3);not null" json:"expected_currency"`
+	ReceivedCurrency string          `gorm:"type:varchar(3)" json:"received_currency,omitempty"`
+	Source           string          `gorm:"type:varchar(100);not null" json:"source"` // ex: webhook:blupay
+	RawStatus        string          `gorm:"type:varchar(100)" json:"raw_status,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (d *PaymentDiscrepancy) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
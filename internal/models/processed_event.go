@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// ProcessedEvent registra os eventos já tratados por cada consumer.
+// A entrega é at-least-once, então o consumer consulta esta tabela pelo event_id do envelope.
+type ProcessedEvent struct {
+	Consumer    string    `gorm:"type:varchar(100);primaryKey" json:"consumer"`
+	EventID     string    `gorm:"type:varchar(36);primaryKey" json:"event_id"`
+	Type        string    `gorm:"type:varchar(100);not null" json:"type"`
+	ProcessedAt time.Time `gorm:"not null;index" json:"processed_at"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RefundStatus string
+
+const (
+	RefundStatusPending   RefundStatus = "pending"
+	RefundStatusSucceeded RefundStatus = "succeeded"
+)
+
+// Refund registra cada estorno (total ou parcial) de um pedido
+type Refund struct {
+	ID              uuid.UUID    `gorm:"type:uuid;primary_key" json:"id"`
+	OrderID         uuid.UUID    `gorm:"type:uuid;not null;index" json:"order_id"`
+	Amount          int          `gorm:"not null" json:"amount"` // em centavos
+	Status          RefundStatus `gorm:"type:varchar(20);not null" json:"status"`
+	Source          string       `gorm:"type:varchar(100);not null" json:"source"` // api ou webhook:<gateway>
+	GatewayRefundID string       `gorm:"type:varchar(255)" json:"gateway_refund_id,omitempty"`
+	Reason          string       `gorm:"type:text" json:"reason,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (r *Refund) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
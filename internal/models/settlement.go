@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type SettlementItemKind string
+
+const (
+	SettlementMatched        SettlementItemKind = "matched"
+	SettlementMissing        SettlementItemKind = "missing"         // pedido pago que não está no extrato
+	SettlementExtra          SettlementItemKind = "extra"           // linha do extrato sem pedido
+	SettlementAmountMismatch SettlementItemKind = "amount_mismatch" // valor do extrato diferente do pedido
+)
+
+// SettlementReport é o resultado da conciliação de um extrato de liquidação do gateway
+type SettlementReport struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Gateway    string     `gorm:"type:varchar(50);not null;index" json:"gateway"`
+	Filename   string     `gorm:"type:varchar(255)" json:"filename,omitempty"`
+	Format     string     `gorm:"type:varchar(10);not null" json:"format"`
+	PeriodFrom *time.Time `json:"period_from,omitempty"` // período usado para buscar pedidos ausentes
+	PeriodTo   *time.Time `json:"period_to,omitempty"`
+	Lines      int        `gorm:"not null" json:"lines"`
+	Matched    int        `gorm:"not null" json:"matched"`
+	Missing    int        `gorm:"not null" json:"missing"`
+	Extra      int        `gorm:"not null" json:"extra"`
+	Mismatched int        `gorm:"not null" json:"mismatched"`
+
+	Items []SettlementItem `gorm:"foreignKey:ReportID" json:"items,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// SettlementItem é uma linha conciliada (ou um pedido ausente do extrato)
+type SettlementItem struct {
+	ID            uuid.UUID          `gorm:"type:uuid;primary_key" json:"id"`
+	ReportID      uuid.UUID          `gorm:"type:uuid;not null;index" json:"report_id"`
+	Kind          SettlementItemKind `gorm:"type:varchar(20);not null;index" json:"kind"`
+	Row           int                `json:"row,omitempty"` // linha no extrato (0 para missing)
+	OrderID       *uuid.UUID         `gorm:"type:uuid;index" json:"order_id,omitempty"`
+	TransactionID string             `gorm:"type:varchar(255)" json:"transaction_id,omitempty"`
+	ExternalRef   string             `gorm:"type:varchar(255)" json:"external_ref,omitempty"`
+	OrderAmount   int                `json:"order_amount"`   // em centavos
+	SettledAmount int                `json:"settled_amount"` // em centavos
+	SettledFee    int                `json:"settled_fee"`
+	SettledNet    int                `json:"settled_net"`
+	OrderStatus   OrderStatus        `gorm:"type:varchar(50)" json:"order_status,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *SettlementReport) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+func (i *SettlementItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == uuid.Nil {
+		i.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead" // desistiu após WEBHOOK_DELIVERY_HORIZON_HOURS
+)
+
+// WebhookDelivery é uma notificação para o WebhookURL do pedido.
+// Gravada na transação que mudou o pedido e entregue pelo WebhookDispatcher.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:uuid;primary_key" json:"id"`
+	OrderID        uuid.UUID             `gorm:"type:uuid;not null;index" json:"order_id"`
+	Event          string                `gorm:"type:varchar(100);not null" json:"event"`
+	URL            string                `gorm:"type:text;not null" json:"url"`
+	Payload        string                `gorm:"type:jsonb;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(20);not null;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"not null;index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at"`
+	LastStatusCode int                   `json:"last_status_code,omitempty"`
+	LastResponse   string                `gorm:"type:text" json:"last_response,omitempty"`
+	LastError      string                `gorm:"type:text" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	Redelivery     bool                  `gorm:"not null;default:false" json:"redelivery"` // reenvio manual pela API
+
+	AttemptLog []WebhookDeliveryAttempt `gorm:"foreignKey:DeliveryID" json:"attempt_log,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (d *WebhookDelivery) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	if d.Status == "" {
+		d.Status = WebhookDeliveryPending
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = time.Now()
+	}
+	return nil
+}
+
+// WebhookDeliveryAttempt registra cada POST feito para uma entrega
+type WebhookDeliveryAttempt struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	DeliveryID     uuid.UUID `gorm:"type:uuid;not null;index" json:"delivery_id"`
+	OrderID        uuid.UUID `gorm:"type:uuid;not null;index" json:"order_id"`
+	Attempt        int       `gorm:"not null" json:"attempt"`
+	URL            string    `gorm:"type:text;not null" json:"url"`
+	RequestHeaders string    `gorm:"type:jsonb" json:"request_headers"`
+	StatusCode     int       `json:"status_code,omitempty"`
+	ResponseBody   string    `gorm:"type:text" json:"response_body,omitempty"` // trecho inicial da resposta
+	Error          string    `gorm:"type:text" json:"error,omitempty"`
+	LatencyMs      int64     `json:"latency_ms"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (a *WebhookDeliveryAttempt) BeforeCreate(tx *gorm.DB) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	return nil
+}
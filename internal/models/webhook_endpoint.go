@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookEndpoint guarda o segredo de assinatura de um webhook_url de lojista.
+// Na rotação o segredo anterior continua assinando até PreviousSecretExpiresAt.
+type WebhookEndpoint struct {
+	ID                      uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	URL                     string     `gorm:"type:text;not null;uniqueIndex" json:"url"`
+	Secret                  string     `gorm:"type:varchar(100);not null" json:"-"`
+	PreviousSecret          string     `gorm:"type:varchar(100)" json:"-"`
+	PreviousSecretExpiresAt *time.Time `json:"previous_secret_expires_at,omitempty"`
+	Events                  []string   `gorm:"type:jsonb;serializer:json" json:"events"` // vazio = todos os eventos
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+func (e *WebhookEndpoint) BeforeCreate(tx *gorm.DB) error {
+	if e.ID == uuid.Nil {
+		e.ID = uuid.New()
+	}
+	return nil
+}
+
+// Subscribes indica se o endpoint recebe o evento
+func (e *WebhookEndpoint) Subscribes(event string) bool {
+	if len(e.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range e.Events {
+		if subscribed == event {
+			return true
+		}
+	}
+	return false
+}
+
+// ActiveSecrets devolve os segredos que assinam as entregas agora (atual primeiro)
+func (e *WebhookEndpoint) ActiveSecrets(now time.Time) []string {
+	secrets := []string{e.Secret}
+	if e.PreviousSecret != "" && e.PreviousSecretExpiresAt != nil && now.Before(*e.PreviousSecretExpiresAt) {
+		secrets = append(secrets, e.PreviousSecret)
+	}
+	return secrets
+}
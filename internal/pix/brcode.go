@@ -0,0 +1,218 @@
+// Package pix lê o BR Code (EMV-MPM) do PIX copia e cola: campos TLV, CRC16,
+// recebedor, txid e valor.
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrMalformed indica que o código não segue o formato TLV do BR Code
+	ErrMalformed = errors.New("BR Code malformado")
+	// ErrInvalidCRC indica que o CRC16 do código não confere
+	ErrInvalidCRC = errors.New("CRC do BR Code inválido")
+	// ErrAmountMismatch indica valor codificado diferente do valor do pedido
+	ErrAmountMismatch = errors.New("valor do BR Code difere do pedido")
+)
+
+// IDs dos campos EMV usados pelo PIX
+const (
+	idPayloadFormat     = "00"
+	idPointOfInitiation = "01"
+	idMerchantAccount   = "26"
+	idMCC               = "52"
+	idCurrency          = "53"
+	idAmount            = "54"
+	idCountry           = "58"
+	idMerchantName      = "59"
+	idMerchantCity      = "60"
+	idAdditionalData    = "62"
+	idCRC               = "63"
+
+	// Subcampos do merchant account (26) e do additional data (62)
+	idGUI   = "00"
+	idKey   = "01"
+	idURL   = "25"
+	idTxid  = "05"
+	pixGUI  = "br.gov.bcb.pix"
+	crcTail = idCRC + "04"
+)
+
+// Field é um campo TLV do BR Code
+type Field struct {
+	ID    string
+	Value string
+}
+
+// BRCode são os campos do PIX copia e cola já interpretados
+type BRCode struct {
+	PayloadFormat string  `json:"payload_format"`
+	Dynamic       bool    `json:"dynamic"` // point of initiation 12: cobrança de uso único
+	Key           string  `json:"key,omitempty"`
+	URL           string  `json:"url,omitempty"` // location do PIX dinâmico
+	MCC           string  `json:"mcc,omitempty"`
+	Currency      string  `json:"currency,omitempty"`
+	Amount        int     `json:"amount,omitempty"` // em centavos; 0 quando o código não traz valor
+	HasAmount     bool    `json:"has_amount"`
+	Country       string  `json:"country,omitempty"`
+	MerchantName  string  `json:"merchant_name,omitempty"`
+	MerchantCity  string  `json:"merchant_city,omitempty"`
+	Txid          string  `json:"txid,omitempty"`
+	CRC           string  `json:"crc"`
+	CRCValid      bool    `json:"crc_valid"`
+	Fields        []Field `json:"-"`
+}
+
+// Parse lê o BR Code. Só retorna erro quando o TLV está malformado;
+// o CRC é verificado e informado em CRCValid.
+func Parse(code string) (*BRCode, error) {
+	code = strings.TrimSpace(code)
+
+	fields, err := parseTLV(code)
+	if err != nil {
+		return nil, err
+	}
+
+	br := &BRCode{Fields: fields}
+	for _, f := range fields {
+		switch f.ID {
+		case idPayloadFormat:
+			br.PayloadFormat = f.Value
+		case idPointOfInitiation:
+			br.Dynamic = f.Value == "12"
+		case idMerchantAccount:
+			sub, err := parseTLV(f.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: merchant account: %v", ErrMalformed, err)
+			}
+			if gui := lookup(sub, idGUI); !strings.EqualFold(gui, pixGUI) {
+				return nil, fmt.Errorf("%w: GUI %q não é PIX", ErrMalformed, gui)
+			}
+			br.Key = lookup(sub, idKey)
+			br.URL = lookup(sub, idURL)
+		case idMCC:
+			br.MCC = f.Value
+		case idCurrency:
+			br.Currency = f.Value
+		case idAmount:
+			amount, err := parseAmount(f.Value)
+			if err != nil {
+				return nil, err
+			}
+			br.Amount = amount
+			br.HasAmount = true
+		case idCountry:
+			br.Country = f.Value
+		case idMerchantName:
+			br.MerchantName = f.Value
+		case idMerchantCity:
+			br.MerchantCity = f.Value
+		case idAdditionalData:
+			sub, err := parseTLV(f.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: additional data: %v", ErrMalformed, err)
+			}
+			br.Txid = lookup(sub, idTxid)
+		case idCRC:
+			br.CRC = strings.ToUpper(f.Value)
+		}
+	}
+
+	if br.PayloadFormat != "01" {
+		return nil, fmt.Errorf("%w: payload format %q", ErrMalformed, br.PayloadFormat)
+	}
+	if br.Key == "" && br.URL == "" {
+		return nil, fmt.Errorf("%w: sem chave nem URL PIX", ErrMalformed)
+	}
+
+	// O CRC é sempre o último campo e cobre tudo até "6304" inclusive
+	if len(code) < 8 || code[len(code)-8:len(code)-4] != crcTail {
+		return nil, fmt.Errorf("%w: CRC ausente ou fora do final", ErrMalformed)
+	}
+	br.CRCValid = CRC16(code[:len(code)-4]) == br.CRC
+
+	return br, nil
+}
+
+// Validate confere CRC e valor esperado (em centavos). Códigos sem valor são aceitos.
+func (b *BRCode) Validate(expectedAmount int) error {
+	if !b.CRCValid {
+		return ErrInvalidCRC
+	}
+	if b.HasAmount && b.Amount != expectedAmount {
+		return fmt.Errorf("%w: código %d, pedido %d", ErrAmountMismatch, b.Amount, expectedAmount)
+	}
+	return nil
+}
+
+// CRC16 calcula o CRC16-CCITT (polinômio 0x1021, inicial 0xFFFF) em hexadecimal maiúsculo
+func CRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+// parseTLV lê a sequência ID(2) + tamanho(2) + valor
+func parseTLV(data string) ([]Field, error) {
+	var fields []Field
+	for i := 0; i < len(data); {
+		if i+4 > len(data) {
+			return nil, fmt.Errorf("%w: campo truncado na posição %d", ErrMalformed, i)
+		}
+
+		id := data[i : i+2]
+		size, err := strconv.Atoi(data[i+2 : i+4])
+		if err != nil {
+			return nil, fmt.Errorf("%w: tamanho inválido no campo %s", ErrMalformed, id)
+		}
+
+		start := i + 4
+		if start+size > len(data) {
+			return nil, fmt.Errorf("%w: campo %s excede o código", ErrMalformed, id)
+		}
+
+		fields = append(fields, Field{ID: id, Value: data[start : start+size]})
+		i = start + size
+	}
+	return fields, nil
+}
+
+func lookup(fields []Field, id string) string {
+	for _, f := range fields {
+		if f.ID == id {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// parseAmount converte "123.45" em centavos sem passar por float
+func parseAmount(value string) (int, error) {
+	reais, cents, _ := strings.Cut(value, ".")
+	if len(cents) > 2 {
+		return 0, fmt.Errorf("%w: valor %q com mais de 2 casas", ErrMalformed, value)
+	}
+	cents += strings.Repeat("0", 2-len(cents))
+
+	r, err := strconv.Atoi(reais)
+	if err != nil || r < 0 {
+		return 0, fmt.Errorf("%w: valor %q", ErrMalformed, value)
+	}
+	c, err := strconv.Atoi(cents)
+	if err != nil || c < 0 {
+		return 0, fmt.Errorf("%w: valor %q", ErrMalformed, value)
+	}
+	return r*100 + c, nil
+}
@@ -0,0 +1,169 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// tlv monta um campo ID + tamanho + valor
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// brCode monta um BR Code com os campos informados e o CRC16 correto no final
+func brCode(fields ...string) string {
+	code := ""
+	for _, f := range fields {
+		code += f
+	}
+	code += crcTail
+	return code + CRC16(code)
+}
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"", "FFFF"},
+		{"123456789", "29B1"}, // vetor de referência do CRC-16/CCITT-FALSE
+		{"A", "B915"},
+	}
+
+	for _, tt := range tests {
+		if got := CRC16(tt.data); got != tt.want {
+			t.Errorf("CRC16(%q) = %s, esperado %s", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	static := brCode(
+		tlv(idPayloadFormat, "01"),
+		tlv(idMerchantAccount, tlv(idGUI, "br.gov.bcb.pix")+tlv(idKey, "chave@exemplo.com")),
+		tlv(idMCC, "0000"),
+		tlv(idCurrency, "986"),
+		tlv(idAmount, "27.90"),
+		tlv(idCountry, "BR"),
+		tlv(idMerchantName, "LOJA"),
+		tlv(idMerchantCity, "SAO PAULO"),
+		tlv(idAdditionalData, tlv(idTxid, "TX123")),
+	)
+	dynamic := brCode(
+		tlv(idPayloadFormat, "01"),
+		tlv(idPointOfInitiation, "12"),
+		tlv(idMerchantAccount, tlv(idGUI, "BR.GOV.BCB.PIX")+tlv(idURL, "pix.exemplo.com/qr/v2/abc")),
+		tlv(idCurrency, "986"),
+		tlv(idCountry, "BR"),
+	)
+	badCRC := static[:len(static)-4] + "0000"
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+		check   func(t *testing.T, br *BRCode)
+	}{
+		{
+			name: "estático com valor",
+			code: static,
+			check: func(t *testing.T, br *BRCode) {
+				if br.Key != "chave@exemplo.com" || br.Txid != "TX123" || br.MerchantName != "LOJA" || br.MerchantCity != "SAO PAULO" {
+					t.Errorf("campos = %+v", br)
+				}
+				if !br.HasAmount || br.Amount != 2790 {
+					t.Errorf("valor = %d (HasAmount %v), esperado 2790", br.Amount, br.HasAmount)
+				}
+				if br.Dynamic || !br.CRCValid {
+					t.Errorf("Dynamic = %v, CRCValid = %v", br.Dynamic, br.CRCValid)
+				}
+			},
+		},
+		{
+			name: "dinâmico sem valor",
+			code: "  " + dynamic + "\n",
+			check: func(t *testing.T, br *BRCode) {
+				if !br.Dynamic || br.URL != "pix.exemplo.com/qr/v2/abc" || br.HasAmount || !br.CRCValid {
+					t.Errorf("campos = %+v", br)
+				}
+			},
+		},
+		{
+			name: "CRC errado não é erro de parse",
+			code: badCRC,
+			check: func(t *testing.T, br *BRCode) {
+				if br.CRCValid {
+					t.Error("CRCValid = true, esperado false")
+				}
+			},
+		},
+		{"vazio", "", ErrMalformed, nil},
+		{"campo truncado", "000201260", ErrMalformed, nil},
+		{"tamanho não numérico", "00AB01", ErrMalformed, nil},
+		{"campo maior que o código", "000901", ErrMalformed, nil},
+		{"payload format diferente de 01", brCode(tlv(idPayloadFormat, "02"), tlv(idMerchantAccount, tlv(idGUI, pixGUI)+tlv(idKey, "k"))), ErrMalformed, nil},
+		{"GUI que não é PIX", brCode(tlv(idPayloadFormat, "01"), tlv(idMerchantAccount, tlv(idGUI, "br.com.outro")+tlv(idKey, "k"))), ErrMalformed, nil},
+		{"sem chave nem URL", brCode(tlv(idPayloadFormat, "01"), tlv(idMerchantAccount, tlv(idGUI, pixGUI))), ErrMalformed, nil},
+		{"valor com 3 casas", brCode(tlv(idPayloadFormat, "01"), tlv(idMerchantAccount, tlv(idGUI, pixGUI)+tlv(idKey, "k")), tlv(idAmount, "1.234")), ErrMalformed, nil},
+		{"sem CRC no final", tlv(idPayloadFormat, "01") + tlv(idMerchantAccount, tlv(idGUI, pixGUI)+tlv(idKey, "k")), ErrMalformed, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			br, err := Parse(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Parse() = %v, esperado %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, br)
+			}
+		})
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int
+		wantErr bool
+	}{
+		{"27.90", 2790, false},
+		{"27.9", 2790, false},
+		{"27", 2700, false},
+		{"0.01", 1, false},
+		{"1.234", 0, true},
+		{"-1.00", 0, true},
+		{"abc", 0, true},
+		{"1.-5", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := parseAmount(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseAmount(%q) = %d, %v; esperado %d, erro %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		br       BRCode
+		expected int
+		want     error
+	}{
+		{"valor confere", BRCode{CRCValid: true, HasAmount: true, Amount: 2790}, 2790, nil},
+		{"sem valor é aceito", BRCode{CRCValid: true}, 2790, nil},
+		{"valor diferente", BRCode{CRCValid: true, HasAmount: true, Amount: 100}, 2790, ErrAmountMismatch},
+		{"CRC inválido", BRCode{HasAmount: true, Amount: 2790}, 2790, ErrInvalidCRC},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.br.Validate(tt.expected); !errors.Is(err, tt.want) {
+				t.Errorf("Validate() = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
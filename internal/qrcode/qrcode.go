@@ -0,0 +1,143 @@
+// Package qrcode gera a imagem do QR Code PIX localmente (PNG, SVG ou data URI),
+// sem enviar o código para serviços externos.
+package qrcode
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	goqrcode "github.com/skip2/go-qrcode"
+)
+
+const (
+	DefaultSize   = 256
+	DefaultMargin = 4 // quiet zone recomendada pela especificação, em módulos
+
+	MinSize   = 64
+	MaxSize   = 2048
+	MaxMargin = 16
+)
+
+// Options controla o tamanho da imagem (px) e a margem (em módulos)
+type Options struct {
+	Size   int
+	Margin int
+}
+
+// Normalize aplica padrões e limites
+func (o Options) Normalize() Options {
+	if o.Size <= 0 {
+		o.Size = DefaultSize
+	}
+	o.Size = min(max(o.Size, MinSize), MaxSize)
+	if o.Margin < 0 {
+		o.Margin = DefaultMargin
+	}
+	o.Margin = min(o.Margin, MaxMargin)
+	return o
+}
+
+// matrix devolve os módulos do QR Code com a margem já aplicada
+func matrix(content string, margin int) ([][]bool, error) {
+	if content == "" {
+		return nil, fmt.Errorf("conteúdo do QR Code vazio")
+	}
+
+	q, err := goqrcode.New(content, goqrcode.Medium)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao gerar QR Code: %w", err)
+	}
+	q.DisableBorder = true
+
+	symbol := q.Bitmap()
+	total := len(symbol) + 2*margin
+
+	modules := make([][]bool, total)
+	for y := range modules {
+		modules[y] = make([]bool, total)
+	}
+	for y, row := range symbol {
+		copy(modules[y+margin][margin:], row)
+	}
+	return modules, nil
+}
+
+// PNG gera a imagem PNG. Cada módulo ocupa um número inteiro de pixels,
+// então a imagem pode sair um pouco menor que opts.Size.
+func PNG(content string, opts Options) ([]byte, error) {
+	opts = opts.Normalize()
+
+	modules, err := matrix(content, opts.Margin)
+	if err != nil {
+		return nil, err
+	}
+
+	scale := max(opts.Size/len(modules), 1)
+	side := len(modules) * scale
+
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+	for y, row := range modules {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					img.SetColorIndex(x*scale+dx, y*scale+dy, 1)
+				}
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("erro ao codificar PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SVG gera a imagem vetorial (um único path com os módulos escuros)
+func SVG(content string, opts Options) ([]byte, error) {
+	opts = opts.Normalize()
+
+	modules, err := matrix(content, opts.Margin)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(modules)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, opts.Size, opts.Size, total, total)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/><path fill="#000" d="`, total, total)
+
+	// Agrupa módulos escuros consecutivos da mesma linha em um retângulo
+	for y, row := range modules {
+		for x := 0; x < total; x++ {
+			if !row[x] {
+				continue
+			}
+			start := x
+			for x < total && row[x] {
+				x++
+			}
+			fmt.Fprintf(&buf, "M%d %dh%dv1h-%dz", start, y, x-start, x-start)
+		}
+	}
+
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes(), nil
+}
+
+// DataURI gera o PNG como data URI base64 (data:image/png;base64,...)
+func DataURI(content string, opts Options) (string, error) {
+	data, err := PNG(content, opts)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(data), nil
+}
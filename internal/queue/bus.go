@@ -0,0 +1,81 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"gorm.io/gorm"
+)
+
+// Implementações de EventBus
+const (
+	BusRabbitMQ = "rabbitmq"
+	BusRedis    = "redis"
+	BusPostgres = "postgres"
+)
+
+// EventBus publica e consome os eventos das filas em Queues.
+// Todas as implementações têm a mesma semântica: entrega at-least-once, handler
+// chamado em série por fila, retentativa com RetryPolicy e .dlq ao esgotar.
+type EventBus interface {
+	Name() string
+	PublishWithContext(ctx context.Context, queue string, data interface{}) error
+	Publish(queue string, data interface{}) error
+	// Consume registra o handler; erro devolvido agenda nova tentativa
+	Consume(queue string, handler func([]byte) error) error
+	Status() Status
+	Close()
+
+	DeadLetterStats() ([]DeadLetterStats, error)
+	PeekDeadLetters(queue string, limit int) ([]DeadLetter, error)
+	ReplayDeadLetters(ctx context.Context, queue string, limit int) (int, error)
+	PurgeDeadLetters(queue string) (int, error)
+}
+
+// Message é implementado por mensagens com metadados próprios (events.Envelope):
+// o id vira o message-id e os headers acompanham a mensagem, para o consumer
+// deduplicar e checar a versão sem abrir o corpo.
+type Message interface {
+	MessageID() string
+	Headers() map[string]interface{}
+}
+
+// Open escolhe o barramento por EVENT_BUS. Sem EVENT_BUS tenta o RabbitMQ e,
+// se não houver, usa o Postgres para os eventos não se perderem.
+func Open(cfg *config.Config, db *gorm.DB, redisClient *redis.Client) (EventBus, error) {
+	switch cfg.EventBus {
+	case BusRabbitMQ:
+		return Connect(cfg.RabbitMQURL)
+	case BusRedis:
+		return NewRedisStreamsBus(redisClient), nil
+	case BusPostgres:
+		return NewPostgresBus(db, time.Duration(cfg.EventRetention)*time.Hour), nil
+	case "":
+		if cfg.RabbitMQURL == "" {
+			log.Println("ℹ️ RABBITMQ_URL vazio, usando barramento de eventos no Postgres")
+			return NewPostgresBus(db, time.Duration(cfg.EventRetention)*time.Hour), nil
+		}
+
+		rabbitMQ, err := Connect(cfg.RabbitMQURL)
+		if err != nil {
+			log.Printf("⚠️ RabbitMQ não conectado: %v (usando barramento de eventos no Postgres)", err)
+			return NewPostgresBus(db, time.Duration(cfg.EventRetention)*time.Hour), nil
+		}
+		return rabbitMQ, nil
+	default:
+		return nil, fmt.Errorf("EVENT_BUS inválido: %s (use rabbitmq, redis ou postgres)", cfg.EventBus)
+	}
+}
+
+// checkQueue garante que a fila existe em todos os barramentos
+func checkQueue(queue string) error {
+	if !slices.Contains(Queues, queue) {
+		return fmt.Errorf("%w: %s", ErrUnknownQueue, queue)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrUnknownQueue indica uma fila que não está em Queues
+var ErrUnknownQueue = errors.New("fila desconhecida")
+
+// DeadLetterStats resume uma fila e as suas filas de retentativa
+type DeadLetterStats struct {
+	Queue       string `json:"queue"`
+	MaxAttempts int    `json:"max_attempts"`
+	RetryDelay  string `json:"retry_delay"`
+	Retrying    int    `json:"retrying"`
+	DeadLetters int    `json:"dead_letters"`
+}
+
+// DeadLetter é uma mensagem parada na .dlq
+type DeadLetter struct {
+	MessageID string                 `json:"message_id,omitempty"`
+	Attempts  int                    `json:"attempts"`
+	LastError string                 `json:"last_error,omitempty"`
+	FailedAt  string                 `json:"failed_at,omitempty"`
+	Headers   map[string]interface{} `json:"headers,omitempty"`
+	Body      string                 `json:"body"`
+}
+
+// deadLetterStats monta o resumo de todas as filas a partir das contagens do barramento
+func deadLetterStats(counts func(queue string) (retrying, deadLetters int, err error)) ([]DeadLetterStats, error) {
+	stats := make([]DeadLetterStats, 0, len(Queues))
+
+	for _, queue := range Queues {
+		retrying, deadLetters, err := counts(queue)
+		if err != nil {
+			return nil, err
+		}
+
+		policy := PolicyFor(queue)
+		stats = append(stats, DeadLetterStats{
+			Queue:       queue,
+			MaxAttempts: policy.MaxAttempts,
+			RetryDelay:  policy.Delay.String(),
+			Retrying:    retrying,
+			DeadLetters: deadLetters,
+		})
+	}
+
+	return stats, nil
+}
+
+// adminChannel abre um canal separado para as operações administrativas;
+// fechar o canal devolve à fila tudo o que foi lido sem ack.
+func (r *RabbitMQ) adminChannel(queue string) (*amqp.Channel, error) {
+	if err := checkQueue(queue); err != nil {
+		return nil, err
+	}
+
+	r.mu.RLock()
+	conn, state := r.conn, r.status.State
+	r.mu.RUnlock()
+
+	if state != StateConnected {
+		return nil, ErrNotConnected
+	}
+
+	return conn.Channel()
+}
+
+// DeadLetterStats lista, para cada fila, quantas mensagens aguardam retentativa e quantas estão na .dlq
+func (r *RabbitMQ) DeadLetterStats() ([]DeadLetterStats, error) {
+	return deadLetterStats(func(queue string) (int, int, error) {
+		ch, err := r.adminChannel(queue)
+		if err != nil {
+			return 0, 0, err
+		}
+		defer ch.Close()
+
+		retry, err := ch.QueueDeclarePassive(RetryQueue(queue), true, false, false, false, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+		dlq, err := ch.QueueDeclarePassive(DeadLetterQueue(queue), true, false, false, false, nil)
+		if err != nil {
+			return 0, 0, err
+		}
+
+		return retry.Messages, dlq.Messages, nil
+	})
+}
+
+// PeekDeadLetters lê até limit mensagens da .dlq sem removê-las
+func (r *RabbitMQ) PeekDeadLetters(queue string, limit int) ([]DeadLetter, error) {
+	ch, err := r.adminChannel(queue)
+	if err != nil {
+		return nil, err
+	}
+	// Sem ack: as mensagens voltam para a .dlq ao fechar o canal
+	defer ch.Close()
+
+	letters := []DeadLetter{}
+	for len(letters) < limit {
+		msg, ok, err := ch.Get(DeadLetterQueue(queue), false)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+
+		letter := DeadLetter{
+			MessageID: msg.MessageId,
+			Attempts:  Attempts(msg.Headers),
+			Headers:   msg.Headers,
+			Body:      string(msg.Body),
+		}
+		letter.LastError, _ = msg.Headers[HeaderLastError].(string)
+		letter.FailedAt, _ = msg.Headers[HeaderFailedAt].(string)
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
+
+// ReplayDeadLetters devolve até limit mensagens da .dlq para a fila original com as tentativas zeradas
+func (r *RabbitMQ) ReplayDeadLetters(ctx context.Context, queue string, limit int) (int, error) {
+	ch, err := r.adminChannel(queue)
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	replayed := 0
+	for replayed < limit {
+		msg, ok, err := ch.Get(DeadLetterQueue(queue), false)
+		if err != nil {
+			return replayed, err
+		}
+		if !ok {
+			break
+		}
+
+		headers := amqp.Table{}
+		for k, v := range msg.Headers {
+			headers[k] = v
+		}
+		delete(headers, HeaderAttempts)
+		delete(headers, HeaderFailedAt)
+		headers["x-replayed-at"] = time.Now().UTC().Format(time.RFC3339)
+
+		publishCtx, cancel := context.WithTimeout(ctx, PublishTimeout)
+		err = r.publish(publishCtx, queue, amqp.Publishing{
+			Headers:     headers,
+			ContentType: msg.ContentType,
+			MessageId:   msg.MessageId,
+			Timestamp:   msg.Timestamp,
+			Body:        msg.Body,
+		})
+		cancel()
+		if err != nil {
+			msg.Nack(false, true)
+			return replayed, err
+		}
+
+		if err := msg.Ack(false); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+// PurgeDeadLetters apaga todas as mensagens da .dlq e devolve quantas eram
+func (r *RabbitMQ) PurgeDeadLetters(queue string) (int, error) {
+	ch, err := r.adminChannel(queue)
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	return ch.QueuePurge(DeadLetterQueue(queue), false)
+}
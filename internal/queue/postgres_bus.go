@@ -0,0 +1,315 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	postgresBusPollInterval = time.Second
+	postgresBusBatchSize    = 10
+	// Prazo que uma mensagem fica reservada para o consumer que a pegou;
+	// se o processo morrer ela volta a ficar disponível depois disso
+	postgresBusLease = 5 * time.Minute
+	// Intervalo da limpeza das mensagens pendentes mais antigas que a retenção
+	postgresBusCleanupInterval = 10 * time.Minute
+)
+
+var _ EventBus = (*PostgresBus)(nil)
+
+// PostgresBus guarda as mensagens na tabela bus_messages e os consumers fazem
+// polling com SKIP LOCKED, então funciona com várias instâncias e sem broker.
+type PostgresBus struct {
+	db        *gorm.DB
+	started   time.Time
+	retention time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// NewPostgresBus cria o barramento. Com retention > 0 as mensagens pendentes mais
+// antigas que isso são apagadas: filas sem consumer (payment.*) não crescem para sempre.
+// Mensagens na DLQ ficam até alguém agir.
+func NewPostgresBus(db *gorm.DB, retention time.Duration) *PostgresBus {
+	b := &PostgresBus{
+		db:        db,
+		started:   time.Now(),
+		retention: retention,
+		stop:      make(chan struct{}),
+	}
+
+	if retention > 0 {
+		go b.expireLoop()
+	}
+
+	return b
+}
+
+func (b *PostgresBus) expireLoop() {
+	ticker := time.NewTicker(postgresBusCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		b.expire()
+
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (b *PostgresBus) expire() {
+	result := b.db.Where("status = ? AND created_at < ?", models.BusMessagePending, time.Now().Add(-b.retention)).
+		Delete(&models.BusMessage{})
+	if result.Error != nil {
+		log.Printf("❌ [EventBus] Erro ao apagar mensagens expiradas: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("🧹 [EventBus] %d mensagens pendentes há mais de %s apagadas", result.RowsAffected, b.retention)
+	}
+}
+
+func (b *PostgresBus) Name() string {
+	return BusPostgres
+}
+
+func (b *PostgresBus) Publish(queue string, data interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), PublishTimeout)
+	defer cancel()
+
+	return b.PublishWithContext(ctx, queue, data)
+}
+
+func (b *PostgresBus) PublishWithContext(ctx context.Context, queue string, data interface{}) error {
+	if err := checkQueue(queue); err != nil {
+		return ErrUnroutable
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return b.db.WithContext(ctx).Create(&models.BusMessage{
+		Queue:   queue,
+		Payload: string(body),
+	}).Error
+}
+
+func (b *PostgresBus) Consume(queue string, handler func([]byte) error) error {
+	if err := checkQueue(queue); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(postgresBusPollInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-b.stop:
+				return
+			case <-ticker.C:
+			}
+
+			// Continua lendo enquanto vierem lotes cheios
+			for {
+				n, err := b.consumeBatch(queue, handler)
+				if err != nil {
+					log.Printf("❌ [EventBus] Erro ao ler fila %s: %v", queue, err)
+				}
+				if err != nil || n < postgresBusBatchSize {
+					break
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (b *PostgresBus) consumeBatch(queue string, handler func([]byte) error) (int, error) {
+	now := time.Now()
+
+	var messages []models.BusMessage
+	err := b.db.Raw(`
+		UPDATE bus_messages SET available_at = ?
+		WHERE id IN (
+			SELECT id FROM bus_messages
+			WHERE queue = ? AND status = ? AND available_at <= ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now.Add(postgresBusLease), queue, models.BusMessagePending, now, postgresBusBatchSize,
+	).Scan(&messages).Error
+	if err != nil {
+		return 0, err
+	}
+
+	sort.Slice(messages, func(i, j int) bool {
+		return messages[i].CreatedAt.Before(messages[j].CreatedAt)
+	})
+
+	for i := range messages {
+		b.handle(&messages[i], handler)
+	}
+
+	return len(messages), nil
+}
+
+// handle apaga a mensagem processada ou agenda a próxima tentativa (ou a .dlq)
+func (b *PostgresBus) handle(msg *models.BusMessage, handler func([]byte) error) {
+	handlerErr := handler([]byte(msg.Payload))
+	if handlerErr == nil {
+		if err := b.db.Delete(&models.BusMessage{}, "id = ?", msg.ID).Error; err != nil {
+			log.Printf("❌ [EventBus] Erro ao remover mensagem %s de %s: %v", msg.ID, msg.Queue, err)
+		}
+		return
+	}
+
+	log.Printf("Erro ao processar mensagem da fila %s: %v", msg.Queue, handlerErr)
+
+	policy := PolicyFor(msg.Queue)
+	now := time.Now()
+	updates := map[string]interface{}{
+		"attempts":     msg.Attempts + 1,
+		"last_error":   handlerErr.Error(),
+		"available_at": now.Add(policy.Delay),
+	}
+	if msg.Attempts+1 >= policy.MaxAttempts {
+		updates["status"] = models.BusMessageDead
+		updates["failed_at"] = now
+		log.Printf("☠️ [EventBus] Mensagem %s de %s enviada para a DLQ após %d tentativas: %v", msg.ID, msg.Queue, msg.Attempts+1, handlerErr)
+	} else {
+		log.Printf("🔁 [EventBus] Mensagem %s de %s agendada para nova tentativa (%d/%d)", msg.ID, msg.Queue, msg.Attempts+1, policy.MaxAttempts)
+	}
+
+	if err := b.db.Model(&models.BusMessage{}).Where("id = ?", msg.ID).Updates(updates).Error; err != nil {
+		log.Printf("❌ [EventBus] Erro ao agendar nova tentativa de %s: %v", msg.ID, err)
+	}
+}
+
+func (b *PostgresBus) Status() Status {
+	status := Status{State: StateConnected, Since: b.started}
+
+	select {
+	case <-b.stop:
+		status.State = StateClosed
+		return status
+	default:
+	}
+
+	sqlDB, err := b.db.DB()
+	if err == nil {
+		err = sqlDB.Ping()
+	}
+	if err != nil {
+		status.State = StateReconnecting
+		status.LastError = err.Error()
+	}
+
+	return status
+}
+
+func (b *PostgresBus) Close() {
+	b.stopOnce.Do(func() { close(b.stop) })
+}
+
+func (b *PostgresBus) DeadLetterStats() ([]DeadLetterStats, error) {
+	var rows []struct {
+		Queue       string
+		Retrying    int
+		DeadLetters int
+	}
+	if err := b.db.Model(&models.BusMessage{}).
+		Select("queue, COUNT(*) FILTER (WHERE status = ? AND attempts > 0) AS retrying, COUNT(*) FILTER (WHERE status = ?) AS dead_letters",
+			models.BusMessagePending, models.BusMessageDead).
+		Group("queue").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(rows))
+	retrying := make(map[string]int, len(rows))
+	for _, row := range rows {
+		counts[row.Queue] = row.DeadLetters
+		retrying[row.Queue] = row.Retrying
+	}
+
+	return deadLetterStats(func(queue string) (int, int, error) {
+		return retrying[queue], counts[queue], nil
+	})
+}
+
+func (b *PostgresBus) PeekDeadLetters(queue string, limit int) ([]DeadLetter, error) {
+	if err := checkQueue(queue); err != nil {
+		return nil, err
+	}
+
+	var messages []models.BusMessage
+	if err := b.db.Where("queue = ? AND status = ?", queue, models.BusMessageDead).
+		Order("created_at").
+		Limit(limit).
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+
+	letters := make([]DeadLetter, 0, len(messages))
+	for _, msg := range messages {
+		letter := DeadLetter{
+			MessageID: msg.ID.String(),
+			Attempts:  msg.Attempts,
+			LastError: msg.LastError,
+			Body:      msg.Payload,
+		}
+		if msg.FailedAt != nil {
+			letter.FailedAt = msg.FailedAt.UTC().Format(time.RFC3339)
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
+
+func (b *PostgresBus) ReplayDeadLetters(ctx context.Context, queue string, limit int) (int, error) {
+	if err := checkQueue(queue); err != nil {
+		return 0, err
+	}
+
+	result := b.db.WithContext(ctx).Exec(`
+		UPDATE bus_messages
+		SET status = ?, attempts = 0, failed_at = NULL, available_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM bus_messages
+			WHERE queue = ? AND status = ?
+			ORDER BY created_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)`,
+		models.BusMessagePending, time.Now(), time.Now(), queue, models.BusMessageDead, limit,
+	)
+
+	return int(result.RowsAffected), result.Error
+}
+
+func (b *PostgresBus) PurgeDeadLetters(queue string) (int, error) {
+	if err := checkQueue(queue); err != nil {
+		return 0, err
+	}
+
+	result := b.db.Where("queue = ? AND status = ?", queue, models.BusMessageDead).Delete(&models.BusMessage{})
+	return int(result.RowsAffected), result.Error
+}
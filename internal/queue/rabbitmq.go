@@ -1,54 +1,138 @@
 package queue
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+const (
+	reconnectMinBackoff = time.Second
+	reconnectMaxBackoff = 30 * time.Second
+
+	// PublishTimeout é quanto Publish espera pelo ack do broker
+	PublishTimeout = 5 * time.Second
+)
+
+// Estados da conexão expostos em /health
+const (
+	StateConnected    = "connected"
+	StateReconnecting = "reconnecting"
+	StateClosed       = "closed"
+)
+
+// ErrNotConnected é devolvido por Publish enquanto a conexão está sendo refeita.
+// Não há buffer em memória: quem publica pelo outbox tenta de novo depois.
+var ErrNotConnected = errors.New("rabbitmq desconectado")
+
+var (
+	// ErrPublishNacked indica que o broker recusou a mensagem (nack)
+	ErrPublishNacked = errors.New("rabbitmq recusou a mensagem")
+	// ErrUnroutable indica que nenhuma fila recebeu a mensagem (mandatory)
+	ErrUnroutable = errors.New("mensagem sem fila de destino")
+	// ErrConfirmTimeout indica que o ack não chegou dentro do prazo
+	ErrConfirmTimeout = errors.New("timeout aguardando confirmação do rabbitmq")
+)
+
+// RabbitMQ mantém a conexão e o canal, refazendo os dois quando o broker cai:
+// as filas são declaradas de novo e os consumers registrados voltam a consumir.
 type RabbitMQ struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
+	url string
+
+	mu        sync.RWMutex
+	conn      *amqp.Connection
+	channel   *amqp.Channel
+	returns   chan amqp.Return
+	consumers []consumer
+	status    Status
+
+	// Publishes são serializados para casar cada basic.return com a mensagem publicada
+	publishMu sync.Mutex
+}
+
+type consumer struct {
+	queue   string
+	handler func([]byte) error
+}
+
+// Status é o estado atual da conexão
+type Status struct {
+	State      string    `json:"state"`
+	Since      time.Time `json:"since"`
+	Reconnects int       `json:"reconnects"`
+	LastError  string    `json:"last_error,omitempty"`
 }
 
 func Connect(url string) (*RabbitMQ, error) {
-	conn, err := amqp.Dial(url)
+	conn, ch, returns, err := dial(url)
 	if err != nil {
 		return nil, err
 	}
 
+	rmq := &RabbitMQ{
+		url:     url,
+		conn:    conn,
+		channel: ch,
+		returns: returns,
+		status:  Status{State: StateConnected, Since: time.Now()},
+	}
+	go rmq.watch(conn, ch)
+
+	return rmq, nil
+}
+
+// dial abre conexão e canal em modo confirm e declara as filas
+func dial(url string) (*amqp.Connection, *amqp.Channel, chan amqp.Return, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
 	ch, err := conn.Channel()
 	if err != nil {
 		conn.Close()
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	rmq := &RabbitMQ{
-		conn:    conn,
-		channel: ch,
+	if err := ch.Confirm(false); err != nil {
+		ch.Close()
+		conn.Close()
+		return nil, nil, nil, err
 	}
+	returns := ch.NotifyReturn(make(chan amqp.Return, 16))
 
 	// Declara as filas
-	if err := rmq.declareQueues(); err != nil {
+	if err := declareQueues(ch); err != nil {
 		ch.Close()
 		conn.Close()
-		return nil, err
+		return nil, nil, nil, err
 	}
 
-	return rmq, nil
+	return conn, ch, returns, nil
 }
 
-func (r *RabbitMQ) declareQueues() error {
-	queues := []string{
-		"payment.created",
-		"payment.approved",
-		"utmify.pending",
-		"utmify.approved",
-	}
+// Queues são as filas declaradas na conexão; cada uma ganha também .retry e .dlq
+var Queues = []string{
+	"payment.created",
+	"payment.approved",
+	"payment.refunded",
+	"payment.cancelled",
+	"payment.expired",
+	"payment.needs_review",
+	"utmify.pending",
+	"utmify.approved",
+}
 
-	for _, queue := range queues {
-		_, err := r.channel.QueueDeclare(
+func declareQueues(ch *amqp.Channel) error {
+	for _, queue := range Queues {
+		_, err := ch.QueueDeclare(
 			queue, // name
 			true,  // durable
 			false, // delete when unused
@@ -59,48 +143,252 @@ func (r *RabbitMQ) declareQueues() error {
 		if err != nil {
 			return err
 		}
+
+		if err := declareRetryQueues(ch, queue); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// watch espera a conexão ou o canal caírem e dispara a reconexão
+func (r *RabbitMQ) watch(conn *amqp.Connection, ch *amqp.Channel) {
+	connClosed := conn.NotifyClose(make(chan *amqp.Error, 1))
+	chClosed := ch.NotifyClose(make(chan *amqp.Error, 1))
+
+	var reason *amqp.Error
+	select {
+	case reason = <-connClosed:
+	case reason = <-chClosed:
+	}
+
+	r.mu.Lock()
+	if r.status.State == StateClosed {
+		r.mu.Unlock()
+		return
+	}
+	r.status = Status{State: StateReconnecting, Since: time.Now(), Reconnects: r.status.Reconnects}
+	if reason != nil {
+		r.status.LastError = reason.Error()
+	}
+	r.mu.Unlock()
+
+	log.Printf("⚠️ [RabbitMQ] Conexão perdida (%v), reconectando", reason)
+
+	// Canal caiu sozinho: fecha a conexão para recomeçar do zero
+	conn.Close()
+
+	r.reconnect()
+}
+
+// reconnect tenta de novo com backoff exponencial até conseguir ou Close ser chamado
+func (r *RabbitMQ) reconnect() {
+	backoff := reconnectMinBackoff
+
+	for {
+		time.Sleep(backoff)
+
+		r.mu.RLock()
+		closed := r.status.State == StateClosed
+		r.mu.RUnlock()
+		if closed {
+			return
+		}
+
+		conn, ch, returns, err := dial(r.url)
+		if err != nil {
+			r.mu.Lock()
+			r.status.LastError = err.Error()
+			r.mu.Unlock()
+
+			log.Printf("⚠️ [RabbitMQ] Falha ao reconectar: %v (nova tentativa em %s)", err, backoff)
+			backoff = min(backoff*2, reconnectMaxBackoff)
+			continue
+		}
+
+		r.mu.Lock()
+		if r.status.State == StateClosed {
+			r.mu.Unlock()
+			ch.Close()
+			conn.Close()
+			return
+		}
+
+		r.conn = conn
+		r.channel = ch
+		r.returns = returns
+		r.status = Status{State: StateConnected, Since: time.Now(), Reconnects: r.status.Reconnects + 1}
+
+		for _, c := range r.consumers {
+			if err := r.consume(ch, c); err != nil {
+				log.Printf("❌ [RabbitMQ] Erro ao reassinar fila %s: %v", c.queue, err)
+			}
+		}
+		r.mu.Unlock()
+
+		log.Printf("✅ [RabbitMQ] Reconectado (%d consumers reassinados)", len(r.consumers))
+
+		go r.watch(conn, ch)
+		return
+	}
+}
+
+var _ EventBus = (*RabbitMQ)(nil)
+
+func (r *RabbitMQ) Name() string {
+	return BusRabbitMQ
+}
+
+// Status devolve o estado da conexão (usado em /health)
+func (r *RabbitMQ) Status() Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.status
+}
+
+// Publish publica e espera o ack do broker por até PublishTimeout
 func (r *RabbitMQ) Publish(queue string, data interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), PublishTimeout)
+	defer cancel()
+
+	return r.PublishWithContext(ctx, queue, data)
+}
+
+// PublishWithContext publica com mandatory=true e só retorna nil depois do ack.
+// Mensagem sem fila de destino (basic.return), nack ou prazo do ctx viram erro.
+func (r *RabbitMQ) PublishWithContext(ctx context.Context, queue string, data interface{}) error {
 	body, err := json.Marshal(data)
 	if err != nil {
 		return err
 	}
 
-	return r.channel.Publish(
+	msg := amqp.Publishing{
+		ContentType: "application/json",
+		Timestamp:   time.Now(),
+		Body:        body,
+	}
+	if m, ok := data.(Message); ok {
+		msg.MessageId = m.MessageID()
+		msg.Headers = amqp.Table(m.Headers())
+	}
+
+	return r.publish(ctx, queue, msg)
+}
+
+// publish envia msg à fila pela exchange padrão e espera o ack
+func (r *RabbitMQ) publish(ctx context.Context, queue string, msg amqp.Publishing) error {
+	r.publishMu.Lock()
+	defer r.publishMu.Unlock()
+
+	r.mu.RLock()
+	ch, returns, state := r.channel, r.returns, r.status.State
+	r.mu.RUnlock()
+
+	if state != StateConnected {
+		log.Printf("❌ [RabbitMQ] Publish em %s recusado: %s", queue, state)
+		return ErrNotConnected
+	}
+
+	// Descarta returns atrasados de publishes anteriores que estouraram o prazo
+	drainReturns(returns, "")
+
+	// O MessageId identifica o basic.return desta mensagem (retentativas mantêm o original)
+	if msg.MessageId == "" {
+		msg.MessageId = uuid.New().String()
+	}
+	messageID := msg.MessageId
+
+	confirmation, err := ch.PublishWithDeferredConfirmWithContext(
+		ctx,
 		"",    // exchange
 		queue, // routing key
-		false, // mandatory
+		true,  // mandatory
 		false, // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-		},
+		msg,
 	)
+	if err != nil {
+		return err
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		log.Printf("❌ [RabbitMQ] Sem confirmação para %s (%s): %v", queue, messageID, err)
+		return fmt.Errorf("%w: %v", ErrConfirmTimeout, err)
+	}
+	if !acked {
+		log.Printf("❌ [RabbitMQ] Broker recusou mensagem para %s (%s)", queue, messageID)
+		return ErrPublishNacked
+	}
+
+	// O basic.return chega antes do ack, então já está no canal se houve
+	if ret, ok := drainReturns(returns, messageID); ok {
+		log.Printf("❌ [RabbitMQ] Mensagem para %s sem fila de destino: %d %s", queue, ret.ReplyCode, ret.ReplyText)
+		return fmt.Errorf("%w: %s (%s)", ErrUnroutable, queue, ret.ReplyText)
+	}
+
+	return nil
 }
 
+// drainReturns esvazia o canal de returns e devolve o da mensagem messageID, se houver
+func drainReturns(returns chan amqp.Return, messageID string) (amqp.Return, bool) {
+	var found amqp.Return
+	var ok bool
+
+	for {
+		select {
+		case ret, open := <-returns:
+			if !open {
+				return found, ok
+			}
+			if messageID != "" && ret.MessageId == messageID {
+				found, ok = ret, true
+				continue
+			}
+			log.Printf("⚠️ [RabbitMQ] Return atrasado para %s (%s): %s", ret.RoutingKey, ret.MessageId, ret.ReplyText)
+		default:
+			return found, ok
+		}
+	}
+}
+
+// Consume registra o handler da fila; ele volta a consumir sozinho após cada reconexão
 func (r *RabbitMQ) Consume(queue string, handler func([]byte) error) error {
-	msgs, err := r.channel.Consume(
-		queue, // queue
-		"",    // consumer
-		false, // auto-ack
-		false, // exclusive
-		false, // no-local
-		false, // no-wait
-		nil,   // args
+	c := consumer{queue: queue, handler: handler}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.status.State == StateConnected {
+		if err := r.consume(r.channel, c); err != nil {
+			return err
+		}
+	}
+
+	r.consumers = append(r.consumers, c)
+	return nil
+}
+
+func (r *RabbitMQ) consume(ch *amqp.Channel, c consumer) error {
+	msgs, err := ch.Consume(
+		c.queue, // queue
+		"",      // consumer
+		false,   // auto-ack
+		false,   // exclusive
+		false,   // no-local
+		false,   // no-wait
+		nil,     // args
 	)
 	if err != nil {
 		return err
 	}
 
+	// O range termina quando o canal cai; a reconexão abre um novo consumer
 	go func() {
 		for msg := range msgs {
-			if err := handler(msg.Body); err != nil {
-				log.Printf("Erro ao processar mensagem da fila %s: %v", queue, err)
-				msg.Nack(false, true) // requeue
+			if err := c.handler(msg.Body); err != nil {
+				log.Printf("Erro ao processar mensagem da fila %s: %v", c.queue, err)
+				r.retry(c.queue, msg, err)
 			} else {
 				msg.Ack(false)
 			}
@@ -111,10 +399,15 @@ func (r *RabbitMQ) Consume(queue string, handler func([]byte) error) error {
 }
 
 func (r *RabbitMQ) Close() {
-	if r.channel != nil {
-		r.channel.Close()
+	r.mu.Lock()
+	r.status = Status{State: StateClosed, Since: time.Now(), Reconnects: r.status.Reconnects}
+	ch, conn := r.channel, r.conn
+	r.mu.Unlock()
+
+	if ch != nil {
+		ch.Close()
 	}
-	if r.conn != nil {
-		r.conn.Close()
+	if conn != nil {
+		conn.Close()
 	}
 }
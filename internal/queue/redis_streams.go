@@ -0,0 +1,374 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	redisStreamPrefix   = "events:"
+	redisConsumerGroup  = "server-apis"
+	redisStreamMaxLen   = 100000
+	redisReadBlock      = 2 * time.Second
+	redisReadCount      = 10
+	redisRetryBatchSize = 50
+	// Mensagens pendentes há mais tempo que isso são retomadas de consumers que morreram
+	redisClaimIdle = 5 * time.Minute
+)
+
+var _ EventBus = (*RedisStreamsBus)(nil)
+
+// RedisStreamsBus usa um stream por fila com consumer group. As retentativas
+// ficam num sorted set (score = horário da próxima tentativa) e a .dlq é outro stream.
+type RedisStreamsBus struct {
+	redis    *redis.Client
+	consumer string
+	started  time.Time
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// redisRetry é o membro do sorted set de retentativas
+type redisRetry struct {
+	ID        string `json:"id"`
+	Data      string `json:"data"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error"`
+}
+
+func NewRedisStreamsBus(client *redis.Client) *RedisStreamsBus {
+	consumer, err := os.Hostname()
+	if err != nil || consumer == "" {
+		consumer = uuid.New().String()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &RedisStreamsBus{
+		redis:    client,
+		consumer: consumer,
+		started:  time.Now(),
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+func redisStream(queue string) string {
+	return redisStreamPrefix + queue
+}
+
+func (b *RedisStreamsBus) Name() string {
+	return BusRedis
+}
+
+func (b *RedisStreamsBus) Publish(queue string, data interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), PublishTimeout)
+	defer cancel()
+
+	return b.PublishWithContext(ctx, queue, data)
+}
+
+func (b *RedisStreamsBus) PublishWithContext(ctx context.Context, queue string, data interface{}) error {
+	if err := checkQueue(queue); err != nil {
+		return ErrUnroutable
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	values := map[string]interface{}{"data": string(body)}
+	if m, ok := data.(Message); ok {
+		for k, v := range m.Headers() {
+			values[k] = v
+		}
+	}
+
+	return b.add(ctx, redisStream(queue), values)
+}
+
+func (b *RedisStreamsBus) add(ctx context.Context, stream string, values map[string]interface{}) error {
+	return b.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: stream,
+		MaxLen: redisStreamMaxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+}
+
+func (b *RedisStreamsBus) Consume(queue string, handler func([]byte) error) error {
+	if err := checkQueue(queue); err != nil {
+		return err
+	}
+
+	err := b.redis.XGroupCreateMkStream(b.ctx, redisStream(queue), redisConsumerGroup, "0").Err()
+	if err != nil && !isBusyGroup(err) {
+		return err
+	}
+
+	go b.run(queue, handler)
+	return nil
+}
+
+func isBusyGroup(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP")
+}
+
+func (b *RedisStreamsBus) run(queue string, handler func([]byte) error) {
+	stream := redisStream(queue)
+
+	for b.ctx.Err() == nil {
+		if err := b.promoteRetries(queue); err != nil {
+			log.Printf("❌ [EventBus] Erro ao reenfileirar retentativas de %s: %v", queue, err)
+		}
+
+		claimed, _, err := b.redis.XAutoClaim(b.ctx, &redis.XAutoClaimArgs{
+			Stream:   stream,
+			Group:    redisConsumerGroup,
+			Consumer: b.consumer,
+			MinIdle:  redisClaimIdle,
+			Start:    "0-0",
+			Count:    redisReadCount,
+		}).Result()
+		if err == nil {
+			for _, msg := range claimed {
+				b.handle(queue, msg, handler)
+			}
+		}
+
+		streams, err := b.redis.XReadGroup(b.ctx, &redis.XReadGroupArgs{
+			Group:    redisConsumerGroup,
+			Consumer: b.consumer,
+			Streams:  []string{stream, ">"},
+			Count:    redisReadCount,
+			Block:    redisReadBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if b.ctx.Err() != nil {
+				return
+			}
+			log.Printf("❌ [EventBus] Erro ao ler stream %s: %v", stream, err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		for _, s := range streams {
+			for _, msg := range s.Messages {
+				b.handle(queue, msg, handler)
+			}
+		}
+	}
+}
+
+// handle confirma a mensagem processada; em erro agenda a retentativa ou manda para a .dlq.
+// Se nem isso der certo a mensagem fica pendente e é retomada depois de redisClaimIdle.
+func (b *RedisStreamsBus) handle(queue string, msg redis.XMessage, handler func([]byte) error) {
+	data, _ := msg.Values["data"].(string)
+	attemptsValue, _ := msg.Values["attempts"].(string)
+	attempts, _ := strconv.Atoi(attemptsValue)
+
+	if handlerErr := handler([]byte(data)); handlerErr != nil {
+		log.Printf("Erro ao processar mensagem da fila %s: %v", queue, handlerErr)
+		if err := b.retry(queue, data, attempts+1, handlerErr); err != nil {
+			log.Printf("❌ [EventBus] Erro ao agendar nova tentativa em %s: %v", queue, err)
+			return
+		}
+	}
+
+	if err := b.redis.XAck(b.ctx, redisStream(queue), redisConsumerGroup, msg.ID).Err(); err != nil {
+		log.Printf("❌ [EventBus] Erro ao confirmar mensagem %s de %s: %v", msg.ID, queue, err)
+	}
+}
+
+func (b *RedisStreamsBus) retry(queue, data string, attempts int, handlerErr error) error {
+	policy := PolicyFor(queue)
+
+	if attempts >= policy.MaxAttempts {
+		log.Printf("☠️ [EventBus] Mensagem de %s enviada para a DLQ após %d tentativas: %v", queue, attempts, handlerErr)
+		return b.add(b.ctx, redisStream(DeadLetterQueue(queue)), map[string]interface{}{
+			"data":       data,
+			"attempts":   attempts,
+			"last_error": handlerErr.Error(),
+			"failed_at":  time.Now().UTC().Format(time.RFC3339),
+		})
+	}
+
+	member, err := json.Marshal(redisRetry{
+		ID:        uuid.New().String(),
+		Data:      data,
+		Attempts:  attempts,
+		LastError: handlerErr.Error(),
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🔁 [EventBus] Mensagem de %s agendada para nova tentativa (%d/%d)", queue, attempts, policy.MaxAttempts)
+	return b.redis.ZAdd(b.ctx, redisStream(RetryQueue(queue)), redis.Z{
+		Score:  float64(time.Now().Add(policy.Delay).UnixMilli()),
+		Member: string(member),
+	}).Err()
+}
+
+// promoteRetries devolve ao stream as retentativas vencidas. O ZREM decide qual
+// instância fica com cada uma, então não há publicação duplicada.
+func (b *RedisStreamsBus) promoteRetries(queue string) error {
+	key := redisStream(RetryQueue(queue))
+
+	due, err := b.redis.ZRangeByScore(b.ctx, key, &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(time.Now().UnixMilli(), 10),
+		Count: redisRetryBatchSize,
+	}).Result()
+	if err != nil {
+		return err
+	}
+
+	for _, member := range due {
+		removed, err := b.redis.ZRem(b.ctx, key, member).Result()
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			continue
+		}
+
+		var retry redisRetry
+		if err := json.Unmarshal([]byte(member), &retry); err != nil {
+			log.Printf("❌ [EventBus] Retentativa inválida em %s descartada: %v", key, err)
+			continue
+		}
+
+		if err := b.add(b.ctx, redisStream(queue), map[string]interface{}{
+			"data":     retry.Data,
+			"attempts": retry.Attempts,
+		}); err != nil {
+			// Devolve ao sorted set para a próxima volta
+			b.redis.ZAdd(b.ctx, key, redis.Z{Score: float64(time.Now().UnixMilli()), Member: member})
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (b *RedisStreamsBus) Status() Status {
+	status := Status{State: StateConnected, Since: b.started}
+
+	if b.ctx.Err() != nil {
+		status.State = StateClosed
+		return status
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	if err := b.redis.Ping(ctx).Err(); err != nil {
+		status.State = StateReconnecting
+		status.LastError = err.Error()
+	}
+
+	return status
+}
+
+// Close para os consumers; o cliente Redis é compartilhado e fica aberto
+func (b *RedisStreamsBus) Close() {
+	b.cancel()
+}
+
+func (b *RedisStreamsBus) DeadLetterStats() ([]DeadLetterStats, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), PublishTimeout)
+	defer cancel()
+
+	return deadLetterStats(func(queue string) (int, int, error) {
+		retrying, err := b.redis.ZCard(ctx, redisStream(RetryQueue(queue))).Result()
+		if err != nil {
+			return 0, 0, err
+		}
+		deadLetters, err := b.redis.XLen(ctx, redisStream(DeadLetterQueue(queue))).Result()
+		if err != nil {
+			return 0, 0, err
+		}
+		return int(retrying), int(deadLetters), nil
+	})
+}
+
+func (b *RedisStreamsBus) PeekDeadLetters(queue string, limit int) ([]DeadLetter, error) {
+	if err := checkQueue(queue); err != nil {
+		return nil, err
+	}
+
+	messages, err := b.redis.XRangeN(context.Background(), redisStream(DeadLetterQueue(queue)), "-", "+", int64(limit)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	letters := make([]DeadLetter, 0, len(messages))
+	for _, msg := range messages {
+		letter := DeadLetter{MessageID: msg.ID}
+		letter.Body, _ = msg.Values["data"].(string)
+		letter.LastError, _ = msg.Values["last_error"].(string)
+		letter.FailedAt, _ = msg.Values["failed_at"].(string)
+		if attempts, ok := msg.Values["attempts"].(string); ok {
+			letter.Attempts, _ = strconv.Atoi(attempts)
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
+
+func (b *RedisStreamsBus) ReplayDeadLetters(ctx context.Context, queue string, limit int) (int, error) {
+	if err := checkQueue(queue); err != nil {
+		return 0, err
+	}
+
+	dlq := redisStream(DeadLetterQueue(queue))
+	messages, err := b.redis.XRangeN(ctx, dlq, "-", "+", int64(limit)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	replayed := 0
+	for _, msg := range messages {
+		data, _ := msg.Values["data"].(string)
+		if err := b.add(ctx, redisStream(queue), map[string]interface{}{"data": data}); err != nil {
+			return replayed, err
+		}
+		if err := b.redis.XDel(ctx, dlq, msg.ID).Err(); err != nil {
+			return replayed, err
+		}
+		replayed++
+	}
+
+	return replayed, nil
+}
+
+func (b *RedisStreamsBus) PurgeDeadLetters(queue string) (int, error) {
+	if err := checkQueue(queue); err != nil {
+		return 0, err
+	}
+
+	ctx := context.Background()
+	dlq := redisStream(DeadLetterQueue(queue))
+
+	count, err := b.redis.XLen(ctx, dlq).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	return int(count), b.redis.Del(ctx, dlq).Err()
+}
@@ -0,0 +1,132 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Headers gravados nas mensagens que falharam
+const (
+	HeaderAttempts      = "x-attempts"
+	HeaderLastError     = "x-last-error"
+	HeaderOriginalQueue = "x-original-queue"
+	HeaderFailedAt      = "x-failed-at"
+)
+
+// RetryPolicy define quantas vezes uma mensagem é tentada e quanto espera entre tentativas.
+// Delay vira o x-message-ttl da fila .retry: mudar o valor exige apagar a fila no broker.
+type RetryPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+}
+
+// DefaultRetryPolicy vale para as filas sem política própria
+var DefaultRetryPolicy = RetryPolicy{MaxAttempts: 5, Delay: 30 * time.Second}
+
+// RetryPolicies sobrescreve a política padrão por fila
+var RetryPolicies = map[string]RetryPolicy{
+	// API da Utmify fica fora por minutos: espaça mais e tenta mais vezes
+	"utmify.pending":  {MaxAttempts: 10, Delay: time.Minute},
+	"utmify.approved": {MaxAttempts: 10, Delay: time.Minute},
+}
+
+// PolicyFor devolve a política de retentativa da fila
+func PolicyFor(queue string) RetryPolicy {
+	if policy, ok := RetryPolicies[queue]; ok {
+		return policy
+	}
+	return DefaultRetryPolicy
+}
+
+// RetryQueue é a fila de espera: ao vencer o TTL a mensagem volta para a fila original
+func RetryQueue(queue string) string {
+	return queue + ".retry"
+}
+
+// DeadLetterQueue recebe as mensagens que esgotaram as tentativas
+func DeadLetterQueue(queue string) string {
+	return queue + ".dlq"
+}
+
+func declareRetryQueues(ch *amqp.Channel, queue string) error {
+	policy := PolicyFor(queue)
+
+	if _, err := ch.QueueDeclare(RetryQueue(queue), true, false, false, false, amqp.Table{
+		"x-message-ttl":             policy.Delay.Milliseconds(),
+		"x-dead-letter-exchange":    "",
+		"x-dead-letter-routing-key": queue,
+	}); err != nil {
+		return fmt.Errorf("erro ao declarar %s: %w", RetryQueue(queue), err)
+	}
+
+	if _, err := ch.QueueDeclare(DeadLetterQueue(queue), true, false, false, false, nil); err != nil {
+		return fmt.Errorf("erro ao declarar %s: %w", DeadLetterQueue(queue), err)
+	}
+
+	return nil
+}
+
+// retry manda a mensagem que falhou para .retry ou, esgotadas as tentativas, para .dlq.
+// Se nem isso for possível a mensagem volta para a fila original.
+func (r *RabbitMQ) retry(queue string, msg amqp.Delivery, handlerErr error) {
+	attempts := Attempts(msg.Headers) + 1
+
+	headers := amqp.Table{}
+	for k, v := range msg.Headers {
+		headers[k] = v
+	}
+	headers[HeaderAttempts] = int32(attempts)
+	headers[HeaderLastError] = handlerErr.Error()
+	headers[HeaderOriginalQueue] = queue
+
+	target := RetryQueue(queue)
+	if attempts >= PolicyFor(queue).MaxAttempts {
+		target = DeadLetterQueue(queue)
+		headers[HeaderFailedAt] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), PublishTimeout)
+	defer cancel()
+
+	err := r.publish(ctx, target, amqp.Publishing{
+		Headers:     headers,
+		ContentType: msg.ContentType,
+		MessageId:   msg.MessageId,
+		Timestamp:   msg.Timestamp,
+		Body:        msg.Body,
+	})
+	if err != nil {
+		log.Printf("❌ [RabbitMQ] Erro ao mover mensagem de %s para %s: %v", queue, target, err)
+		msg.Nack(false, true)
+		return
+	}
+
+	if target == DeadLetterQueue(queue) {
+		log.Printf("☠️ [RabbitMQ] Mensagem de %s enviada para %s após %d tentativas: %v", queue, target, attempts, handlerErr)
+	} else {
+		log.Printf("🔁 [RabbitMQ] Mensagem de %s agendada para nova tentativa (%d/%d)", queue, attempts, PolicyFor(queue).MaxAttempts)
+	}
+
+	msg.Ack(false)
+}
+
+// Attempts lê o número de tentativas já feitas a partir dos headers
+func Attempts(headers amqp.Table) int {
+	switch v := headers[HeaderAttempts].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	case int16:
+		return int(v)
+	case int8:
+		return int(v)
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package queue
+
+import (
+	"testing"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestAttempts(t *testing.T) {
+	// O broker devolve inteiros com tipos diferentes conforme quem gravou o header
+	tests := map[string]struct {
+		headers amqp.Table
+		want    int
+	}{
+		"sem headers":  {nil, 0},
+		"sem o header": {amqp.Table{"x-outro": int32(3)}, 0},
+		"int32":        {amqp.Table{HeaderAttempts: int32(3)}, 3},
+		"int64":        {amqp.Table{HeaderAttempts: int64(4)}, 4},
+		"int":          {amqp.Table{HeaderAttempts: 5}, 5},
+		"int16":        {amqp.Table{HeaderAttempts: int16(6)}, 6},
+		"int8":         {amqp.Table{HeaderAttempts: int8(7)}, 7},
+		"string":       {amqp.Table{HeaderAttempts: "3"}, 0},
+	}
+
+	for name, tt := range tests {
+		if got := Attempts(tt.headers); got != tt.want {
+			t.Errorf("%s: Attempts() = %d, esperado %d", name, got, tt.want)
+		}
+	}
+}
+
+func TestPolicyFor(t *testing.T) {
+	if got := PolicyFor("utmify.approved"); got != (RetryPolicy{MaxAttempts: 10, Delay: time.Minute}) {
+		t.Errorf("PolicyFor(utmify.approved) = %+v", got)
+	}
+	if got := PolicyFor("payment.approved"); got != DefaultRetryPolicy {
+		t.Errorf("PolicyFor(payment.approved) = %+v, esperado a política padrão %+v", got, DefaultRetryPolicy)
+	}
+	if RetryQueue("x") != "x.retry" || DeadLetterQueue("x") != "x.dlq" {
+		t.Errorf("RetryQueue/DeadLetterQueue = %s, %s", RetryQueue("x"), DeadLetterQueue("x"))
+	}
+}
@@ -1,9 +1,13 @@
 package router
 
 import (
+	"expvar"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
 	"github.com/victtorkaiser/server-apis/internal/handlers"
 	"github.com/victtorkaiser/server-apis/internal/middlewares"
 	"github.com/victtorkaiser/server-apis/internal/queue"
@@ -11,7 +15,7 @@ import (
 	"gorm.io/gorm"
 )
 
-func Setup(db *gorm.DB, redis *redis.Client, rabbitMQ *queue.RabbitMQ, cfg *config.Config) *gin.Engine {
+func Setup(db *gorm.DB, redis *redis.Client, bus queue.EventBus, cfg *config.Config) *gin.Engine {
 	r := gin.Default()
 
 	// Middlewares globais
@@ -19,30 +23,38 @@ func Setup(db *gorm.DB, redis *redis.Client, rabbitMQ *queue.RabbitMQ, cfg *conf
 	r.Use(middlewares.Logger())
 	r.Use(middlewares.Recovery())
 
+	// Gateways de pagamento
+	gatewayRegistry := gateways.NewDefaultRegistry(cfg)
+
 	// Services
-	paymentService := services.NewPaymentService(db, redis, rabbitMQ, cfg)
-	webhookService := services.NewWebhookService(db, redis, rabbitMQ, cfg)
-	utmifyService := services.NewUtmifyService(cfg)
-	quantumPayService := services.NewQuantumPayService(db, redis, rabbitMQ, cfg)
-	bluPayService := services.NewBluPayService(db, redis, rabbitMQ, cfg)
-	mangoFyService := services.NewMangoFyService(db, redis, rabbitMQ, cfg)
-	genesysService := services.NewGenesysService(db, redis, rabbitMQ, cfg)
+	paymentService := services.NewPaymentService(db, redis, bus, cfg, gatewayRegistry)
+	webhookService := services.NewWebhookService(db, redis, bus, cfg)
+	refundService := services.NewRefundService(db, redis, bus, cfg, gatewayRegistry)
+	webhookEndpointService := services.NewWebhookEndpointService(db, cfg)
+	webhookLogService := services.NewWebhookLogService(db)
+	inboundWebhookService := services.NewInboundWebhookService(db, webhookService, gatewayRegistry)
+	settlementService := services.NewSettlementService(db)
 	cpfService := services.NewCPFService(cfg)
 	freeFireService := services.NewFreeFireService()
 
 	// Handlers
 	paymentHandler := handlers.NewPaymentHandler(paymentService)
-	webhookHandler := handlers.NewWebhookHandler(webhookService, utmifyService)
-	healthHandler := handlers.NewHealthHandler(db, redis)
-	quantumPayHandler := handlers.NewQuantumPayHandler(quantumPayService)
-	bluPayHandler := handlers.NewBluPayHandler(bluPayService)
-	mangoFyHandler := handlers.NewMangoFyHandler(mangoFyService, webhookService)
-	genesysHandler := handlers.NewGenesysHandler(genesysService, webhookService)
+	refundHandler := handlers.NewRefundHandler(refundService)
+	webhookEndpointHandler := handlers.NewWebhookEndpointHandler(webhookEndpointService)
+	webhookLogHandler := handlers.NewWebhookLogHandler(webhookLogService)
+	settlementHandler := handlers.NewSettlementHandler(settlementService, gatewayRegistry)
+	webhookHandler := handlers.NewWebhookHandler(inboundWebhookService, gatewayRegistry)
+	healthHandler := handlers.NewHealthHandler(db, redis, bus)
+	deadLetterHandler := handlers.NewDeadLetterHandler(bus)
 	cpfHandler := handlers.NewCPFHandler(cpfService)
 	freeFireHandler := handlers.NewFreeFireHandler(freeFireService)
 
+	// Idempotency-Key nos endpoints de criação de pagamento
+	idempotency := middlewares.Idempotency(redis, time.Duration(cfg.IdempotencyTTL)*time.Hour)
+
 	// Health check
 	r.GET("/health", healthHandler.Check)
+	r.GET("/debug/vars", middlewares.AdminAuth(cfg), gin.WrapH(expvar.Handler())) // métricas (ex: gateway_routing, admin)
 
 	// API v1
 	v1 := r.Group("/api/v1")
@@ -50,29 +62,64 @@ func Setup(db *gorm.DB, redis *redis.Client, rabbitMQ *queue.RabbitMQ, cfg *conf
 		// Pagamentos
 		payments := v1.Group("/payments")
 		{
-			payments.POST("", paymentHandler.Create)
+			payments.POST("", idempotency, paymentHandler.Create)
 			payments.GET("/:id", paymentHandler.GetByID)
+			payments.GET("/:id/qrcode.png", paymentHandler.QRCodePNG)
+			payments.GET("/:id/qrcode.svg", paymentHandler.QRCodeSVG)
 			payments.GET("/transaction/:transaction_id", paymentHandler.GetByTransactionID)
+			payments.POST("/:id/refund", middlewares.AdminAuth(cfg), idempotency, refundHandler.Create)
+			payments.GET("/:id/refunds", middlewares.AdminAuth(cfg), refundHandler.List)
+			payments.GET("/:id/webhooks", middlewares.AdminAuth(cfg), webhookLogHandler.List)
+			payments.POST("/:id/webhooks/redeliver", middlewares.AdminAuth(cfg), webhookLogHandler.Redeliver)
 		}
 
-		// Webhooks
+		// Webhooks (assinatura validada por gateway antes do processamento)
+		middlewares.LogWebhookAuth(cfg)
 		webhooks := v1.Group("/webhooks")
 		{
-			webhooks.POST("/payment", webhookHandler.HandlePayment)
-			webhooks.POST("/blupay", webhookHandler.HandlePayment)      // Rota específica BluPay
-			webhooks.POST("/quantumpay", webhookHandler.HandlePayment)  // Rota específica QuantumPay
-			webhooks.POST("/mangofy", mangoFyHandler.HandleWebhook)     // Rota específica MangoFy
-			webhooks.POST("/genesys", genesysHandler.HandleWebhook)    // Rota específica Genesys
+			webhooks.POST("/payment", middlewares.WebhookSignature(cfg, "payment"), webhookHandler.HandlePayment)
+			webhooks.POST("/:gateway", middlewares.WebhookSignature(cfg, ""), webhookHandler.HandleGateway) // blupay, quantumpay, mangofy, genesys
+		}
+
+		// Webhooks recebidos dos gateways, arquivados para auditoria e reprocessamento (admin)
+		inbound := v1.Group("/inbound-webhooks", middlewares.AdminAuth(cfg))
+		{
+			inbound.GET("", webhookHandler.ListInbound)
+			inbound.POST("/:id/reprocess", webhookHandler.Reprocess)
+		}
+
+		// Conciliação dos extratos de liquidação dos gateways (admin)
+		settlements := v1.Group("/settlements", middlewares.AdminAuth(cfg))
+		{
+			settlements.POST("", settlementHandler.Import)
+			settlements.GET("", settlementHandler.List)
+			settlements.GET("/:id", settlementHandler.Get)
+		}
+
+		// Mensagens que esgotaram as retentativas nas filas (admin)
+		deadLetters := v1.Group("/dead-letters", middlewares.AdminAuth(cfg))
+		{
+			deadLetters.GET("", deadLetterHandler.Stats)
+			deadLetters.GET("/:queue", deadLetterHandler.Peek)
+			deadLetters.POST("/:queue/replay", deadLetterHandler.Replay)
+			deadLetters.DELETE("/:queue", deadLetterHandler.Purge)
+		}
+
+		// Endpoints de webhook dos lojistas e seus segredos de assinatura (admin)
+		endpoints := v1.Group("/webhook-endpoints", middlewares.AdminAuth(cfg))
+		{
+			endpoints.POST("", webhookEndpointHandler.Create)
+			endpoints.GET("", webhookEndpointHandler.List)
+			endpoints.GET("/events", webhookEndpointHandler.Events)
+			endpoints.PUT("/:id/events", webhookEndpointHandler.UpdateEvents)
+			endpoints.POST("/:id/rotate-secret", webhookEndpointHandler.RotateSecret)
 		}
 	}
 
-	// API Payment (QuantumPay, BluPay, MangoFy & Genesys)
+	// API Payment (despacha pelo registry: quantumpay, blupay, mangofy, genesys)
 	payment := r.Group("/api/payment")
 	{
-		payment.POST("/quantumpay", quantumPayHandler.CreatePayment)
-		payment.POST("/blupay", bluPayHandler.CreatePayment)
-		payment.POST("/mangofy", mangoFyHandler.CreatePayment)
-		payment.POST("/genesys", genesysHandler.CreatePayment)
+		payment.POST("/:gateway", idempotency, paymentHandler.CreateGatewayPayment)
 	}
 
 	// API CPF
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"github.com/victtorkaiser/server-apis/internal/queue"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const expirationBatchSize = 100
+
+// ExpirationService expira pedidos cujo PIX venceu sem pagamento
+type ExpirationService struct {
+	db  *gorm.DB
+	bus queue.EventBus
+	cfg *config.Config
+}
+
+func NewExpirationService(db *gorm.DB, bus queue.EventBus, cfg *config.Config) *ExpirationService {
+	return &ExpirationService{
+		db:  db,
+		bus: bus,
+		cfg: cfg,
+	}
+}
+
+// ExpireOverdue expira um lote de pedidos pendentes vencidos e devolve quantos foram expirados
+func (s *ExpirationService) ExpireOverdue(ctx context.Context) (int, error) {
+	var expired []models.Order
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var orders []models.Order
+
+		// SKIP LOCKED evita disputa com webhooks e com outras instâncias do worker
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND expires_at IS NOT NULL AND expires_at <= ?",
+				[]models.OrderStatus{models.OrderStatusPending, models.OrderStatusWaitingPayment}, time.Now()).
+			Order("expires_at").
+			Limit(expirationBatchSize).
+			Find(&orders).Error; err != nil {
+			return err
+		}
+
+		for i := range orders {
+			err := expireOrder(tx, &orders[i], "expiration", "")
+			if errors.Is(err, models.ErrSameStatus) || errors.Is(err, models.ErrInvalidTransition) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			expired = append(expired, orders[i])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range expired {
+		order := &expired[i]
+		log.Printf("⌛ [Expiration] Pedido %s expirado (transaction_id=%s, venceu em %s)", order.ID, order.TransactionID, order.ExpiresAt.Format("2006-01-02 15:04:05"))
+	}
+
+	return len(expired), nil
+}
+
+// expireOrder muda o pedido para expired e enfileira payment.expired (webhook do pedido e barramento de eventos).
+// Deve ser chamada dentro de uma transação com o pedido travado.
+func expireOrder(tx *gorm.DB, order *models.Order, source, rawStatus string) error {
+	from := order.Status
+	if err := transitionOrder(tx, order, models.OrderStatusExpired, source, rawStatus); err != nil {
+		return err
+	}
+
+	if err := enqueueWebhookDelivery(tx, order, events.PaymentExpired, from); err != nil {
+		return err
+	}
+
+	if err := enqueueOutbox(tx, events.PaymentExpired, order.ID.String(), events.PaymentExpiredPayload{
+		OrderID:       order.ID,
+		TransactionID: order.TransactionID,
+		FromStatus:    from,
+		Status:        order.Status,
+		Platform:      order.Platform,
+		ExpiresAt:     order.ExpiresAt,
+	}); err != nil {
+		return fmt.Errorf("erro ao enfileirar payment.expired: %w", err)
+	}
+	return nil
+}
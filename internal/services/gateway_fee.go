@@ -0,0 +1,43 @@
+package services
+
+import (
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+// applyGatewayFee copia a tarifa informada pelo gateway para o pedido.
+// Sem netAmount do gateway, o líquido é o valor do pedido menos a tarifa.
+// Devolve false quando não há tarifa ou nada mudou.
+func applyGatewayFee(order *models.Order, fee *gateways.Fee) bool {
+	if fee == nil {
+		return false
+	}
+
+	netAmount := fee.NetAmount
+	if netAmount == 0 {
+		netAmount = order.Amount - fee.Amount
+	}
+
+	if order.GatewayFee == fee.Amount &&
+		order.FeeFixedAmount == fee.FixedAmount &&
+		order.FeeSpreadPercentage == fee.SpreadPercentage &&
+		order.NetAmount == netAmount {
+		return false
+	}
+
+	order.GatewayFee = fee.Amount
+	order.FeeFixedAmount = fee.FixedAmount
+	order.FeeSpreadPercentage = fee.SpreadPercentage
+	order.NetAmount = netAmount
+	return true
+}
+
+// gatewayFeeColumns são as colunas de tarifa atualizadas pelos webhooks
+func gatewayFeeColumns(order *models.Order) map[string]interface{} {
+	return map[string]interface{}{
+		"gateway_fee":           order.GatewayFee,
+		"fee_fixed_amount":      order.FeeFixedAmount,
+		"fee_spread_percentage": order.FeeSpreadPercentage,
+		"net_amount":            order.NetAmount,
+	}
+}
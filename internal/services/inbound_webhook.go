@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// genericWebhookGateway é o nome gravado para POST /webhooks/payment
+const genericWebhookGateway = "payment"
+
+// ErrInvalidWebhookPayload indica body que o parser do gateway não entende
+var ErrInvalidWebhookPayload = errors.New("payload de webhook inválido")
+
+// InboundWebhookService arquiva os webhooks recebidos dos gateways antes de processá-los
+type InboundWebhookService struct {
+	db       *gorm.DB
+	webhooks *WebhookService
+	gateways *gateways.Registry
+}
+
+func NewInboundWebhookService(db *gorm.DB, webhooks *WebhookService, registry *gateways.Registry) *InboundWebhookService {
+	return &InboundWebhookService{
+		db:       db,
+		webhooks: webhooks,
+		gateways: registry,
+	}
+}
+
+// Receive arquiva o webhook e o processa. Reentregas do mesmo evento (mesmo
+// gateway + event_id) não geram nova linha; só são processadas de novo se a
+// tentativa anterior falhou.
+func (s *InboundWebhookService) Receive(ctx context.Context, gateway string, headers http.Header, body []byte) (*models.InboundWebhook, error) {
+	event, parseErr := s.parse(gateway, body)
+
+	rawHeaders, _ := json.Marshal(headers)
+	record := &models.InboundWebhook{
+		Gateway: gateway,
+		EventID: inboundEventID(event, body),
+		Headers: string(rawHeaders),
+		Body:    string(body),
+	}
+	if event != nil {
+		record.TransactionID = event.TransactionID
+	}
+
+	result := s.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(record)
+	if result.Error != nil {
+		return nil, fmt.Errorf("erro ao arquivar webhook: %w", result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		if err := s.db.WithContext(ctx).
+			Where("gateway = ? AND event_id = ?", record.Gateway, record.EventID).
+			First(record).Error; err != nil {
+			return nil, fmt.Errorf("erro ao carregar webhook arquivado: %w", err)
+		}
+
+		s.db.WithContext(ctx).Model(record).
+			UpdateColumn("duplicate_count", gorm.Expr("duplicate_count + 1"))
+
+		if record.Status == models.InboundWebhookStatusProcessed {
+			log.Printf("ℹ️ [Webhook] Evento duplicado ignorado: gateway=%s event_id=%s", record.Gateway, record.EventID)
+			return record, nil
+		}
+	}
+
+	return record, s.process(ctx, record, event, parseErr)
+}
+
+// Reprocess roda de novo o parse e o processamento de um webhook arquivado
+func (s *InboundWebhookService) Reprocess(ctx context.Context, id uuid.UUID) (*models.InboundWebhook, error) {
+	var record models.InboundWebhook
+	if err := s.db.WithContext(ctx).First(&record, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	log.Printf("🔁 [Webhook] Reprocessando %s (gateway=%s event_id=%s)", record.ID, record.Gateway, record.EventID)
+
+	event, parseErr := s.parse(record.Gateway, []byte(record.Body))
+	if event != nil {
+		record.TransactionID = event.TransactionID
+	}
+
+	return &record, s.process(ctx, &record, event, parseErr)
+}
+
+// List devolve os webhooks arquivados mais recentes, filtrando por gateway/status
+func (s *InboundWebhookService) List(ctx context.Context, gateway, status string, limit int) ([]models.InboundWebhook, error) {
+	query := s.db.WithContext(ctx).Order("received_at DESC").Limit(limit)
+	if gateway != "" {
+		query = query.Where("gateway = ?", gateway)
+	}
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var records []models.InboundWebhook
+	if err := query.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (s *InboundWebhookService) process(ctx context.Context, record *models.InboundWebhook, event *gateways.PaymentEvent, parseErr error) error {
+	err := parseErr
+	if err == nil {
+		err = s.webhooks.ProcessEvent(ctx, event)
+	}
+
+	now := time.Now()
+	record.Attempts++
+	record.ProcessedAt = &now
+	record.Status = models.InboundWebhookStatusProcessed
+	record.Result = "ok"
+	if err != nil {
+		record.Status = models.InboundWebhookStatusFailed
+		record.Result = err.Error()
+	}
+
+	if saveErr := s.db.WithContext(ctx).Model(record).Updates(map[string]interface{}{
+		"transaction_id": record.TransactionID,
+		"status":         record.Status,
+		"result":         record.Result,
+		"attempts":       record.Attempts,
+		"processed_at":   record.ProcessedAt,
+	}).Error; saveErr != nil {
+		log.Printf("⚠️ [Webhook] Erro ao gravar resultado do webhook %s: %v", record.ID, saveErr)
+	}
+
+	return err
+}
+
+// parse interpreta o body com o parser tipado do gateway. Na rota genérica
+// /webhooks/payment o gateway é identificado pelo formato do payload.
+func (s *InboundWebhookService) parse(gateway string, body []byte) (*gateways.PaymentEvent, error) {
+	if gateway == genericWebhookGateway {
+		detected, ok := gateways.DetectWebhookGateway(body)
+		if !ok {
+			return nil, fmt.Errorf("%w: formato não reconhecido em /webhooks/payment", ErrInvalidWebhookPayload)
+		}
+		gateway = detected
+	}
+
+	adapter, ok := s.gateways.Get(gateway)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", gateways.ErrUnknownGateway, gateway)
+	}
+
+	event, err := adapter.ParseWebhook(body)
+	if event != nil {
+		event.Gateway = adapter.Name()
+	}
+
+	switch {
+	case errors.Is(err, gateways.ErrUnknownStatus):
+		// Mantém o evento para arquivar transaction_id e event_id
+		log.Printf("⚠️ [Webhook] %v", err)
+		return event, err
+	case err != nil:
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookPayload, err)
+	}
+
+	return event, nil
+}
+
+// inboundEventID usa o id do evento do gateway; sem ele, o hash do body
+// deduplica ao menos as reentregas idênticas.
+func inboundEventID(event *gateways.PaymentEvent, body []byte) string {
+	if event != nil && event.EventID != "" {
+		return event.EventID
+	}
+
+	sum := sha256.Sum256(body)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
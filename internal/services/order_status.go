@@ -0,0 +1,46 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+)
+
+// transitionOrder aplica a mudança de status pela máquina de estados, grava o status
+// e as datas do ciclo de vida e o histórico. Deve ser chamada dentro de uma transação.
+func transitionOrder(tx *gorm.DB, order *models.Order, next models.OrderStatus, source, rawStatus string) error {
+	from := order.Status
+	if err := order.TransitionTo(next); err != nil {
+		return err
+	}
+
+	if err := tx.Model(order).Updates(orderStatusColumns(order)).Error; err != nil {
+		return fmt.Errorf("erro ao atualizar status: %w", err)
+	}
+
+	return recordStatusHistory(tx, order, from, source, rawStatus)
+}
+
+// orderStatusColumns são as colunas que mudam numa transição de status
+func orderStatusColumns(order *models.Order) map[string]interface{} {
+	return map[string]interface{}{
+		"status":      order.Status,
+		"approved_at": order.ApprovedAt,
+		"refunded_at": order.RefundedAt,
+	}
+}
+
+func recordStatusHistory(tx *gorm.DB, order *models.Order, from models.OrderStatus, source, rawStatus string) error {
+	history := &models.OrderStatusHistory{
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   order.Status,
+		Source:     source,
+		RawStatus:  rawStatus,
+	}
+	if err := tx.Create(history).Error; err != nil {
+		return fmt.Errorf("erro ao gravar histórico de status: %w", err)
+	}
+	return nil
+}
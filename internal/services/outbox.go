@@ -0,0 +1,35 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+)
+
+// enqueueOutbox envelopa o payload e grava o evento na tabela outbox usando a transação recebida.
+// A publicação no barramento de eventos é feita depois pelo OutboxRelay; a fila é o tipo do evento.
+func enqueueOutbox(tx *gorm.DB, eventType events.Type, correlationID string, payload interface{}) error {
+	envelope, err := events.NewEnvelope(eventType, correlationID, payload)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar evento %s: %w", eventType, err)
+	}
+
+	event := &models.OutboxEvent{
+		Queue:   eventType.String(),
+		EventID: envelope.EventID,
+		Payload: string(body),
+	}
+	if err := tx.Create(event).Error; err != nil {
+		return fmt.Errorf("erro ao gravar evento %s no outbox: %w", eventType, err)
+	}
+
+	return nil
+}
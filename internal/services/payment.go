@@ -1,110 +1,265 @@
 package services
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
 	"github.com/victtorkaiser/server-apis/internal/config"
 	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
 	"github.com/victtorkaiser/server-apis/internal/models"
+	"github.com/victtorkaiser/server-apis/internal/pix"
+	"github.com/victtorkaiser/server-apis/internal/qrcode"
 	"github.com/victtorkaiser/server-apis/internal/queue"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrNoPixCode indica pedido sem código PIX para gerar QR Code
+	ErrNoPixCode = errors.New("pedido sem código PIX")
+	// ErrInvalidPixCode indica BR Code inválido devolvido pelo gateway (PIX_VALIDATION=reject)
+	ErrInvalidPixCode = errors.New("código PIX inválido retornado pelo gateway")
+)
+
+// PaymentService orquestra a criação de pagamentos para qualquer gateway do registry
 type PaymentService struct {
 	db       *gorm.DB
 	redis    *redis.Client
-	rabbitMQ *queue.RabbitMQ
+	bus      queue.EventBus
 	cfg      *config.Config
+	gateways *gateways.Registry
+	router   *gateways.Router
 }
 
-func NewPaymentService(db *gorm.DB, redis *redis.Client, rabbitMQ *queue.RabbitMQ, cfg *config.Config) *PaymentService {
+func NewPaymentService(db *gorm.DB, redis *redis.Client, bus queue.EventBus, cfg *config.Config, registry *gateways.Registry) *PaymentService {
 	return &PaymentService{
 		db:       db,
 		redis:    redis,
-		rabbitMQ: rabbitMQ,
+		bus:      bus,
 		cfg:      cfg,
+		gateways: registry,
+		router:   gateways.NewRouter(registry, cfg),
 	}
 }
 
-func (s *PaymentService) CreatePayment(ctx context.Context, req *dto.CreatePaymentRequest) (*dto.CreatePaymentResponse, error) {
-	// Cria customer
+// CreatePayment cria a cobrança no gateway informado, sem failover
+func (s *PaymentService) CreatePayment(ctx context.Context, gatewayName string, req *dto.GatewayPaymentRequest) (*dto.GatewayPaymentResponse, error) {
+	gateway, ok := s.gateways.Get(gatewayName)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", gateways.ErrUnknownGateway, gatewayName)
+	}
+
+	return s.createPayment(ctx, req, func(chargeReq *gateways.ChargeRequest) (*gateways.RoutedCharge, error) {
+		return s.router.CreateCharge(ctx, chargeReq, gateway)
+	})
+}
+
+// CreateRoutedPayment cria a cobrança seguindo GATEWAY_ROUTING, com failover em erros temporários
+func (s *PaymentService) CreateRoutedPayment(ctx context.Context, req *dto.GatewayPaymentRequest) (*dto.GatewayPaymentResponse, error) {
+	return s.createPayment(ctx, req, func(chargeReq *gateways.ChargeRequest) (*gateways.RoutedCharge, error) {
+		return s.router.Route(ctx, chargeReq)
+	})
+}
+
+func (s *PaymentService) createPayment(ctx context.Context, req *dto.GatewayPaymentRequest, createCharge func(*gateways.ChargeRequest) (*gateways.RoutedCharge, error)) (*dto.GatewayPaymentResponse, error) {
+	// Gera dados automaticamente se não fornecidos
+	if err := s.fillMissingData(req); err != nil {
+		log.Printf("⚠️ Erro ao gerar dados automáticos: %v (continuando com dados fornecidos)", err)
+	}
+
+	expiresIn := time.Duration(req.ExpiresIn) * time.Second
+	if expiresIn <= 0 {
+		expiresIn = time.Duration(s.cfg.PixExpiresIn) * time.Second
+	}
+
+	// Chama API do gateway antes de qualquer escrita no banco:
+	// se o gateway falhar não sobra customer/tracking órfão
+	routed, err := createCharge(&gateways.ChargeRequest{
+		Amount:      req.Amount,
+		Name:        req.Name,
+		Email:       req.Email,
+		Document:    req.Document,
+		Phone:       req.GetPhone(),
+		IP:          req.IP,
+		ExternalRef: req.ExternalRef,
+		ExpiresIn:   expiresIn,
+		UTMParams:   req.UTMParams,
+	})
+	if err != nil {
+		return nil, err
+	}
+	gateway, charge := routed.Gateway, routed.Charge
+
+	pixIssue, err := s.validatePixCode(charge, req.Amount)
+	if err != nil {
+		log.Printf("❌ [%s] Cobrança %s recusada: %v", gateway.Platform(), charge.TransactionID, err)
+		return nil, err
+	}
+	if pixIssue != "" {
+		log.Printf("⚠️ [%s] BR Code da cobrança %s com problema: %s", gateway.Platform(), charge.TransactionID, pixIssue)
+	}
+
 	customer := &models.Customer{
 		Name:     req.Name,
 		Email:    req.Email,
 		Document: req.Document,
-		Phone:    req.Telephone,
+		Phone:    req.GetPhone(),
 		Country:  "BR",
+		IP:       req.IP,
 	}
 
-	if err := s.db.Create(customer).Error; err != nil {
-		return nil, fmt.Errorf("erro ao criar customer: %w", err)
+	var trackingParam *models.TrackingParameter
+	if len(req.UTMParams) > 0 {
+		tp := s.mapTrackingParams(req.UTMParams)
+		trackingParam = &tp
 	}
 
-	// Cria tracking parameters se existirem
-	var trackingParamID *uuid.UUID
-	if len(req.UTMParams) > 0 {
-		trackingParam := s.mapTrackingParams(req.UTMParams)
-		if err := s.db.Create(&trackingParam).Error; err != nil {
-			return nil, fmt.Errorf("erro ao criar tracking params: %w", err)
-		}
-		trackingParamID = &trackingParam.ID
+	order := &models.Order{
+		TransactionID:  charge.TransactionID,
+		ExternalRef:    charge.ExternalRef,
+		Status:         models.OrderStatusPending,
+		Amount:         req.Amount,
+		PaymentMethod:  "pix",
+		Platform:       gateway.Platform(),
+		Gateway:        gateway.Name(),
+		FailedGateways: strings.Join(routed.Failed, ","),
+		PixCode:        charge.PixCode,
+		PixIssue:       pixIssue,
+		WebhookURL:     req.WebhookURL,
+		ExpiresAt:      charge.ExpiresAt,
+	}
+	applyGatewayFee(order, charge.Fee)
+	if order.ExpiresAt == nil {
+		expiresAt := time.Now().Add(expiresIn)
+		order.ExpiresAt = &expiresAt
 	}
 
-	// Chama API externa (MangoFy)
-	externalResp, err := s.callMangoFyAPI(req)
+	// Customer, tracking, order e eventos são gravados na mesma transação
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(customer).Error; err != nil {
+			return fmt.Errorf("erro ao criar customer: %w", err)
+		}
+
+		if trackingParam != nil {
+			if err := tx.Create(trackingParam).Error; err != nil {
+				return fmt.Errorf("erro ao criar tracking params: %w", err)
+			}
+			order.TrackingParameterID = &trackingParam.ID
+		}
+
+		order.CustomerID = customer.ID
+		if err := tx.Create(order).Error; err != nil {
+			return fmt.Errorf("erro ao criar order: %w", err)
+		}
+
+		if err := recordStatusHistory(tx, order, "", "api:"+gateway.Name(), ""); err != nil {
+			return err
+		}
+
+		if err := enqueueWebhookDelivery(tx, order, events.PaymentCreated, ""); err != nil {
+			return err
+		}
+
+		// Eventos vão para o outbox e são publicados pelo OutboxRelay
+		if err := enqueueOutbox(tx, events.PaymentCreated, order.ID.String(), events.PaymentCreatedPayload{
+			OrderID:       order.ID,
+			TransactionID: order.TransactionID,
+			Amount:        order.Amount,
+			Platform:      order.Platform,
+			Gateway:       order.Gateway,
+			GatewayFee:    order.GatewayFee,
+			NetAmount:     order.NetAmount,
+			ExpiresAt:     order.ExpiresAt,
+		}); err != nil {
+			return err
+		}
+
+		return enqueueOutbox(tx, events.UtmifyPending, order.ID.String(), events.UtmifyPendingPayload{
+			OrderID:             order.ID,
+			TransactionID:       order.TransactionID,
+			CustomerID:          order.CustomerID,
+			TrackingParameterID: order.TrackingParameterID,
+			Platform:            order.Platform,
+			GatewayFee:          order.GatewayFee,
+		})
+	})
 	if err != nil {
-		return nil, fmt.Errorf("erro ao chamar API externa: %w", err)
+		log.Printf("❌ [%s] Cobrança %s criada no gateway mas não gravada: %v", gateway.Platform(), charge.TransactionID, err)
+		return nil, err
 	}
 
-	// Cria order
-	order := &models.Order{
-		TransactionID:       externalResp.PaymentCode,
-		Status:              models.OrderStatusPending,
-		Amount:              req.Amount,
-		PaymentMethod:       "pix",
-		Platform:            "PayHubr",
-		PixCode:             externalResp.PixCode,
-		CustomerID:          customer.ID,
-		TrackingParameterID: trackingParamID,
-	}
-
-	if err := s.db.Create(order).Error; err != nil {
-		return nil, fmt.Errorf("erro ao criar order: %w", err)
-	}
-
-	// Publica evento na fila (se RabbitMQ estiver disponível)
-	if s.rabbitMQ != nil {
-		s.rabbitMQ.Publish("payment.created", map[string]interface{}{
-			"order_id":       order.ID,
-			"transaction_id": order.TransactionID,
-			"amount":         order.Amount,
-		})
+	order.Customer = *customer
+	order.TrackingParameter = trackingParam
+
+	qrCodeURL := s.generateQRCodeURL(charge, order)
+
+	var qrCodeB64 string
+	if req.QRCodeB64 && order.PixCode != "" {
+		qrCodeB64, err = qrcode.DataURI(order.PixCode, qrcode.Options{Margin: qrcode.DefaultMargin})
+		if err != nil {
+			log.Printf("⚠️ [%s] Erro ao gerar QR Code base64: %v", gateway.Platform(), err)
+		}
 	}
 
-	// Envia para Utmify (pendente)
-	go s.sendToUtmifyPending(order, customer, trackingParamID)
+	return &dto.GatewayPaymentResponse{
+		Success:         true,
+		Gateway:         gateway.Name(),
+		Token:           order.TransactionID,
+		PixCode:         order.PixCode,
+		QRCodeURL:       qrCodeURL,
+		QRCodeB64:       qrCodeB64,
+		Amount:          order.Amount,
+		Nome:            customer.Name,
+		CPF:             customer.Document,
+		ExpiraEm:        s.formatExpiresAt(order.ExpiresAt),
+		ExpiresAt:       order.ExpiresAt,
+		Txid:            charge.Txid,
+		PixCodeLegacy:   order.PixCode,
+		QRCodeURLLegacy: qrCodeURL,
+	}, nil
+}
+
+// CreateDefaultPayment atende POST /api/v1/payments usando o roteamento da configuração
+func (s *PaymentService) CreateDefaultPayment(ctx context.Context, req *dto.CreatePaymentRequest) (*dto.CreatePaymentResponse, error) {
+	resp, err := s.CreateRoutedPayment(ctx, &dto.GatewayPaymentRequest{
+		Amount:    req.Amount,
+		Name:      req.Name,
+		Email:     req.Email,
+		Document:  req.Document,
+		Telephone: req.Telephone,
+		ExpiresIn: req.ExpiresIn,
+		QRCodeB64: req.QRCodeB64,
+		UTMParams: req.UTMParams,
+	})
+	if err != nil {
+		return nil, err
+	}
 
 	return &dto.CreatePaymentResponse{
 		Success:   true,
-		Token:     order.TransactionID,
-		PixCode:   order.PixCode,
-		QRCodeURL: s.generateQRCodeURL(order.PixCode),
-		Amount:    order.Amount,
+		Token:     resp.Token,
+		Gateway:   resp.Gateway,
+		PixCode:   resp.PixCode,
+		QRCodeURL: resp.QRCodeURL,
+		QRCodeB64: resp.QRCodeB64,
+		Amount:    resp.Amount,
+		ExpiresAt: resp.ExpiresAt,
 	}, nil
 }
 
 func (s *PaymentService) GetOrderByID(ctx context.Context, id uuid.UUID) (*models.Order, error) {
 	var order models.Order
-	if err := s.db.Preload("Customer").Preload("TrackingParameter").First(&order, "id = ?", id).Error; err != nil {
+	if err := s.db.Preload("Customer").Preload("TrackingParameter").Preload("StatusHistory", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at")
+	}).First(&order, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &order, nil
@@ -118,68 +273,6 @@ func (s *PaymentService) GetOrderByTransactionID(ctx context.Context, transactio
 	return &order, nil
 }
 
-func (s *PaymentService) callMangoFyAPI(req *dto.CreatePaymentRequest) (*mangoFyResponse, error) {
-	payload := map[string]interface{}{
-		"store_code":      s.cfg.MangoFyAPIKey,
-		"external_code":   fmt.Sprintf("order_%s", uuid.New().String()),
-		"payment_method":  "pix",
-		"payment_format":  "regular",
-		"installments":    1,
-		"payment_amount":  req.Amount,
-		"shipping_amount": 0,
-		"postback_url":    fmt.Sprintf("%s/api/v1/webhooks/payment", s.cfg.WebhookBaseURL),
-		"items": []map[string]interface{}{
-			{
-				"code":   "1",
-				"name":   "Produto",
-				"amount": req.Amount,
-				"total":  1,
-			},
-		},
-		"customer": map[string]interface{}{
-			"email":    req.Email,
-			"name":     req.Name,
-			"document": req.Document,
-			"phone":    req.Telephone,
-		},
-		"pix": map[string]interface{}{
-			"expires_in_days": 1,
-		},
-		"extra": req.UTMParams,
-	}
-
-	body, _ := json.Marshal(payload)
-	httpReq, err := http.NewRequest("POST", s.cfg.MangoFyAPIURL, bytes.NewBuffer(body))
-	if err != nil {
-		return nil, err
-	}
-
-	httpReq.Header.Set("Authorization", s.cfg.MangoFySecret)
-	httpReq.Header.Set("Store-Code", s.cfg.MangoFyAPIKey)
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(httpReq)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	respBody, _ := io.ReadAll(resp.Body)
-	log.Printf("📡 [MangoFy] Response: %s", string(respBody))
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("erro na API MangoFy: status %d", resp.StatusCode)
-	}
-
-	var result mangoFyResponse
-	if err := json.Unmarshal(respBody, &result); err != nil {
-		return nil, err
-	}
-
-	return &result, nil
-}
-
 func (s *PaymentService) mapTrackingParams(params map[string]interface{}) models.TrackingParameter {
 	tp := models.TrackingParameter{}
 
@@ -218,30 +311,118 @@ func (s *PaymentService) mapTrackingParams(params map[string]interface{}) models
 	return tp
 }
 
-func (s *PaymentService) generateQRCodeURL(pixCode string) string {
-	if pixCode == "" {
+// validatePixCode confere CRC e valor do BR Code. Em modo flag devolve o problema
+// para ser gravado no pedido; em modo reject devolve erro.
+func (s *PaymentService) validatePixCode(charge *gateways.Charge, amount int) (string, error) {
+	if s.cfg.PixValidation == "off" {
+		return "", nil
+	}
+
+	br, err := pix.Parse(charge.PixCode)
+	if err == nil {
+		err = br.Validate(amount)
+	}
+	if err == nil {
+		// Alguns gateways não devolvem o txid separado
+		if charge.Txid == "" {
+			charge.Txid = br.Txid
+		}
+		return "", nil
+	}
+
+	if s.cfg.PixValidation == "reject" {
+		return "", fmt.Errorf("%w: %v", ErrInvalidPixCode, err)
+	}
+	return err.Error(), nil
+}
+
+func (s *PaymentService) generateQRCodeURL(charge *gateways.Charge, order *models.Order) string {
+	// Usa URL do gateway primeiro
+	if charge.QRCodeURL != "" {
+		return charge.QRCodeURL
+	}
+	if order.PixCode == "" {
 		return ""
 	}
-	return fmt.Sprintf("https://api.qrserver.com/v1/create-qr-code/?data=%s&size=300x300", pixCode)
+	// QR Code gerado por esta API (GET /api/v1/payments/:id/qrcode.png)
+	return fmt.Sprintf("%s/api/v1/payments/%s/qrcode.png", strings.TrimRight(s.cfg.PublicBaseURL, "/"), order.ID)
 }
 
-func (s *PaymentService) sendToUtmifyPending(order *models.Order, customer *models.Customer, trackingParamID *uuid.UUID) {
-	// Publica na fila para processar de forma assíncrona (se RabbitMQ estiver disponível)
-	if s.rabbitMQ != nil {
-		s.rabbitMQ.Publish("utmify.pending", map[string]interface{}{
-			"order_id":             order.ID,
-			"transaction_id":       order.TransactionID,
-			"customer_id":          customer.ID,
-			"tracking_parameter_id": trackingParamID,
-		})
+// GetQRCode gera a imagem do QR Code PIX do pedido em png ou svg
+func (s *PaymentService) GetQRCode(ctx context.Context, id uuid.UUID, format string, opts qrcode.Options) ([]byte, error) {
+	var order models.Order
+	if err := s.db.WithContext(ctx).Select("id", "pix_code").First(&order, "id = ?", id).Error; err != nil {
+		return nil, err
 	}
+	if order.PixCode == "" {
+		return nil, ErrNoPixCode
+	}
+
+	if format == "svg" {
+		return qrcode.SVG(order.PixCode, opts)
+	}
+	return qrcode.PNG(order.PixCode, opts)
+}
+
+func (s *PaymentService) formatExpiresAt(expiresAt *time.Time) string {
+	if expiresAt == nil {
+		return ""
+	}
+
+	remaining := time.Until(*expiresAt)
+	if remaining < 24*time.Hour {
+		hours := int(math.Ceil(remaining.Hours()))
+		if hours <= 1 {
+			return fmt.Sprintf("%d minutos", max(int(math.Ceil(remaining.Minutes())), 1))
+		}
+		return fmt.Sprintf("%d horas", hours)
+	}
+
+	days := int(math.Round(remaining.Hours() / 24))
+	if days == 1 {
+		return "1 dia"
+	}
+	return fmt.Sprintf("%d dias", days)
 }
 
-type mangoFyResponse struct {
-	PaymentCode string `json:"payment_code"`
-	PixCode     string `json:"pix_code"`
-	Pix         struct {
-		PixQRCodeText string `json:"pix_qrcode_text"`
-		PixLink       string `json:"pix_link"`
-	} `json:"pix"`
+// Preenche dados faltantes automaticamente usando 5devs
+func (s *PaymentService) fillMissingData(req *dto.GatewayPaymentRequest) error {
+	// Verifica se precisa gerar dados
+	needsFakeData := req.Name == "" || req.Email == "" || req.Document == "" || req.GetPhone() == ""
+
+	if !needsFakeData {
+		return nil // Todos os dados fornecidos
+	}
+
+	log.Println("🔄 Dados incompletos detectados, gerando automaticamente via 5devs...")
+
+	// Gera pessoa fake
+	fakerService := NewFakerService()
+	pessoa, err := fakerService.GerarPessoa()
+	if err != nil {
+		return fmt.Errorf("erro ao gerar dados fake: %w", err)
+	}
+
+	// Preenche apenas os campos vazios
+	if req.Name == "" {
+		req.Name = pessoa.Nome
+		log.Printf("✅ Nome gerado: %s", req.Name)
+	}
+
+	if req.Email == "" {
+		req.Email = pessoa.Email
+		log.Printf("✅ Email gerado: %s", req.Email)
+	}
+
+	if req.Document == "" {
+		req.Document = fakerService.CleanCPF(pessoa.CPF)
+		log.Printf("✅ CPF gerado: %s", req.Document)
+	}
+
+	if req.GetPhone() == "" {
+		req.Phone = fakerService.CleanPhone(pessoa.Celular)
+		log.Printf("✅ Telefone gerado: %s", req.Phone)
+	}
+
+	return nil
 }
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"github.com/victtorkaiser/server-apis/internal/queue"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrRefundNotAllowed indica que o pedido não está em um status que permite estorno
+	ErrRefundNotAllowed = errors.New("pedido não pode ser estornado")
+	// ErrInvalidRefundAmount indica valor de estorno menor ou igual a zero ou acima do saldo
+	ErrInvalidRefundAmount = errors.New("valor de estorno inválido")
+)
+
+type RefundService struct {
+	db       *gorm.DB
+	redis    *redis.Client
+	bus      queue.EventBus
+	cfg      *config.Config
+	gateways *gateways.Registry
+}
+
+func NewRefundService(db *gorm.DB, redis *redis.Client, bus queue.EventBus, cfg *config.Config, registry *gateways.Registry) *RefundService {
+	return &RefundService{
+		db:       db,
+		redis:    redis,
+		bus:      bus,
+		cfg:      cfg,
+		gateways: registry,
+	}
+}
+
+// RefundOrder estorna o pedido no gateway e registra o estorno. Sem amount, estorna o saldo restante.
+// O pedido fica travado durante a chamada ao gateway: estornos concorrentes do mesmo pedido
+// esperam e validam o saldo já atualizado, em vez de estornarem duas vezes no gateway.
+// Estorno pendente no gateway só é gravado; o pedido muda quando o webhook confirmar (reconcileRefund).
+func (s *RefundService) RefundOrder(ctx context.Context, orderID uuid.UUID, req *dto.RefundRequest) (*models.Refund, error) {
+	var order models.Order
+	var refund *models.Refund
+	var gatewayRefundID string
+	var refundedAtGateway bool
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "id = ?", orderID).Error; err != nil {
+			return fmt.Errorf("pedido não encontrado: %w", err)
+		}
+
+		var pendingAmount int
+		if err := tx.Model(&models.Refund{}).
+			Where("order_id = ? AND status = ?", order.ID, models.RefundStatusPending).
+			Select("COALESCE(SUM(amount), 0)").Scan(&pendingAmount).Error; err != nil {
+			return fmt.Errorf("erro ao somar estornos pendentes: %w", err)
+		}
+
+		amount, err := refundableAmount(&order, pendingAmount, req.Amount)
+		if err != nil {
+			return err
+		}
+
+		gateway, ok := s.orderGateway(&order)
+		if !ok {
+			return fmt.Errorf("%w: gateway %q, platform %q", gateways.ErrUnknownGateway, order.Gateway, order.Platform)
+		}
+
+		log.Printf("💸 [Refund] Estornando %d centavos do pedido %s (%s)", amount, order.ID, order.Platform)
+
+		result, err := gateway.Refund(ctx, order.TransactionID, amount)
+		if err != nil {
+			return fmt.Errorf("erro ao estornar no %s: %w", gateway.Platform(), err)
+		}
+		gatewayRefundID = result.RefundID
+		refundedAtGateway = true
+
+		in := refundInput{
+			Amount:          amount,
+			Status:          models.RefundStatusSucceeded,
+			Source:          "api",
+			GatewayRefundID: gatewayRefundID,
+			Reason:          req.Reason,
+		}
+		if result.Pending {
+			in.Status = models.RefundStatusPending
+			refund, err = createRefund(tx, &order, in)
+			return err
+		}
+
+		refund, err = applyRefund(tx, &order, in)
+		return err
+	})
+	if err != nil {
+		if refundedAtGateway {
+			log.Printf("❌ [Refund] Estorno %s feito no gateway mas não gravado: %v", gatewayRefundID, err)
+		}
+		return nil, err
+	}
+
+	if refund.Status == models.RefundStatusPending {
+		log.Printf("⏳ [Refund] Estorno %s do pedido %s pendente no gateway, aguardando confirmação", refund.ID, order.ID)
+		return refund, nil
+	}
+
+	log.Printf("✅ [Refund] Pedido %s estornado (%d/%d centavos)", order.ID, order.RefundedAmount, order.Amount)
+
+	return refund, nil
+}
+
+// orderGateway resolve o gateway que criou a cobrança. Pedidos antigos sem Gateway
+// são buscados pelo Platform.
+func (s *RefundService) orderGateway(order *models.Order) (gateways.PaymentGateway, bool) {
+	if order.Gateway != "" {
+		return s.gateways.Get(order.Gateway)
+	}
+	return s.gateways.GetByPlatform(order.Platform)
+}
+
+func (s *RefundService) ListRefunds(ctx context.Context, orderID uuid.UUID) ([]models.Refund, error) {
+	var refunds []models.Refund
+	if err := s.db.WithContext(ctx).Where("order_id = ?", orderID).Order("created_at").Find(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+// refundableAmount valida o pedido e devolve o valor a estornar.
+// Estornos pendentes no gateway já contam como usados do saldo.
+func refundableAmount(order *models.Order, pending, requested int) (int, error) {
+	if !order.Status.IsPaid() && order.Status != models.OrderStatusPartiallyRefunded {
+		return 0, fmt.Errorf("%w: status %s", ErrRefundNotAllowed, order.Status)
+	}
+
+	remaining := order.Amount - order.RefundedAmount - pending
+	if remaining <= 0 {
+		return 0, fmt.Errorf("%w: sem saldo (estornado %d, pendente %d)", ErrInvalidRefundAmount, order.RefundedAmount, pending)
+	}
+	if requested == 0 {
+		return remaining, nil
+	}
+	if requested < 0 || requested > remaining {
+		return 0, fmt.Errorf("%w: solicitado %d, disponível %d", ErrInvalidRefundAmount, requested, remaining)
+	}
+	return requested, nil
+}
+
+type refundInput struct {
+	Amount          int
+	Status          models.RefundStatus
+	Source          string
+	RawStatus       string
+	GatewayRefundID string
+	Reason          string
+}
+
+// applyRefund grava o estorno, atualiza saldo e status do pedido e enfileira payment.refunded
+// (webhook do pedido e barramento de eventos).
+// Deve ser chamada dentro de uma transação com o pedido travado.
+func applyRefund(tx *gorm.DB, order *models.Order, in refundInput) (*models.Refund, error) {
+	if in.Amount <= 0 || in.Amount > order.Amount-order.RefundedAmount {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidRefundAmount, in.Amount)
+	}
+
+	from := order.Status
+	if err := addRefundedAmount(tx, order, in.Amount, in.Source, in.RawStatus); err != nil {
+		return nil, err
+	}
+
+	refund, err := createRefund(tx, order, in)
+	if err != nil {
+		return nil, err
+	}
+
+	return refund, enqueueRefunded(tx, order, refund, from)
+}
+
+// confirmRefund conclui um estorno que estava pendente no gateway: o valor entra no saldo
+// do pedido, o status muda e payment.refunded é enfileirado.
+// Deve ser chamada dentro de uma transação com o pedido travado.
+func confirmRefund(tx *gorm.DB, order *models.Order, refund *models.Refund, source, rawStatus string) error {
+	if refund.Amount > order.Amount-order.RefundedAmount {
+		return fmt.Errorf("%w: estorno %s de %d acima do saldo", ErrInvalidRefundAmount, refund.ID, refund.Amount)
+	}
+
+	from := order.Status
+	if err := addRefundedAmount(tx, order, refund.Amount, source, rawStatus); err != nil {
+		return err
+	}
+
+	refund.Status = models.RefundStatusSucceeded
+	if err := tx.Model(refund).Update("status", refund.Status).Error; err != nil {
+		return fmt.Errorf("erro ao confirmar estorno: %w", err)
+	}
+
+	return enqueueRefunded(tx, order, refund, from)
+}
+
+// addRefundedAmount soma o estorno ao saldo e muda o pedido para refunded ou partially_refunded
+func addRefundedAmount(tx *gorm.DB, order *models.Order, amount int, source, rawStatus string) error {
+	from := order.Status
+	order.RefundedAmount += amount
+	if err := tx.Model(order).Update("refunded_amount", order.RefundedAmount).Error; err != nil {
+		return fmt.Errorf("erro ao atualizar pedido: %w", err)
+	}
+
+	next := models.OrderStatusPartiallyRefunded
+	if order.RefundedAmount >= order.Amount {
+		next = models.OrderStatusRefunded
+	}
+
+	err := transitionOrder(tx, order, next, source, rawStatus)
+	if errors.Is(err, models.ErrSameStatus) {
+		// Novo estorno parcial: status não muda, mas o saldo sim
+		err = recordStatusHistory(tx, order, from, source, rawStatus)
+	}
+	return err
+}
+
+func createRefund(tx *gorm.DB, order *models.Order, in refundInput) (*models.Refund, error) {
+	refund := &models.Refund{
+		OrderID:         order.ID,
+		Amount:          in.Amount,
+		Status:          in.Status,
+		Source:          in.Source,
+		GatewayRefundID: in.GatewayRefundID,
+		Reason:          in.Reason,
+	}
+	if err := tx.Create(refund).Error; err != nil {
+		return nil, fmt.Errorf("erro ao gravar estorno: %w", err)
+	}
+	return refund, nil
+}
+
+func enqueueRefunded(tx *gorm.DB, order *models.Order, refund *models.Refund, from models.OrderStatus) error {
+	if err := enqueueWebhookDelivery(tx, order, events.PaymentRefunded, from); err != nil {
+		return err
+	}
+
+	return enqueueOutbox(tx, events.PaymentRefunded, order.ID.String(), events.PaymentRefundedPayload{
+		OrderID:        order.ID,
+		TransactionID:  order.TransactionID,
+		RefundID:       refund.ID,
+		Amount:         refund.Amount,
+		RefundedAmount: order.RefundedAmount,
+		Status:         order.Status,
+		Platform:       order.Platform,
+	})
+}
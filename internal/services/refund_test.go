@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+func TestRefundableAmount(t *testing.T) {
+	approved := func(refunded int) *models.Order {
+		return &models.Order{Amount: 2790, RefundedAmount: refunded, Status: models.OrderStatusApproved}
+	}
+
+	tests := []struct {
+		name      string
+		order     *models.Order
+		pending   int
+		requested int
+		want      int
+		wantErr   error
+	}{
+		{"total sem valor informado", approved(0), 0, 0, 2790, nil},
+		{"parcial", approved(0), 0, 1000, 1000, nil},
+		{"saldo de pedido parcialmente estornado", &models.Order{Amount: 2790, RefundedAmount: 1000, Status: models.OrderStatusPartiallyRefunded}, 0, 0, 1790, nil},
+		{"pendente reduz o saldo", approved(0), 1000, 0, 1790, nil},
+		{"pedido pago", &models.Order{Amount: 100, Status: models.OrderStatusPaid}, 0, 100, 100, nil},
+		{"acima do saldo com pendente", approved(0), 1000, 1791, 0, ErrInvalidRefundAmount},
+		{"sem saldo", approved(1790), 1000, 0, 0, ErrInvalidRefundAmount},
+		{"valor negativo", approved(0), 0, -1, 0, ErrInvalidRefundAmount},
+		{"pedido pendente", &models.Order{Amount: 2790, Status: models.OrderStatusPending}, 0, 0, 0, ErrRefundNotAllowed},
+		{"pedido já estornado", &models.Order{Amount: 2790, RefundedAmount: 2790, Status: models.OrderStatusRefunded}, 0, 0, 0, ErrRefundNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := refundableAmount(tt.order, tt.pending, tt.requested)
+			if !errors.Is(err, tt.wantErr) || got != tt.want {
+				t.Errorf("refundableAmount() = %d, %v; esperado %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// Em reconcileRefund os pendentes já foram somados em order.RefundedAmount antes do cálculo
+func TestUnrecordedRefund(t *testing.T) {
+	tests := []struct {
+		name      string
+		refunded  int
+		event     int
+		confirmed int
+		want      int
+	}{
+		{"estorno total pelo painel do gateway", 0, 0, 0, 2790},
+		{"total informado pelo gateway", 0, 1000, 0, 1000},
+		{"pendente confirmado sem total no webhook", 1000, 0, 1, 0},
+		{"pendente confirmado e total igual", 1000, 1000, 1, 0},
+		{"total maior que o pendente confirmado", 1000, 1500, 1, 500},
+		{"webhook repetido", 2790, 2790, 0, 0},
+		{"total menor que o registrado", 2000, 1000, 0, -1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order := &models.Order{Amount: 2790, RefundedAmount: tt.refunded}
+			event := &gateways.PaymentEvent{RefundedAmount: tt.event}
+			if got := unrecordedRefund(order, event, tt.confirmed); got != tt.want {
+				t.Errorf("unrecordedRefund() = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
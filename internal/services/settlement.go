@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"github.com/victtorkaiser/server-apis/internal/settlement"
+	"gorm.io/gorm"
+)
+
+const settlementLookupChunk = 500
+
+// ErrInvalidStatement indica extrato que não pôde ser lido (formato, colunas ou valores)
+var ErrInvalidStatement = errors.New("extrato inválido")
+
+// SettlementImport descreve o extrato a conciliar. Com período informado,
+// pedidos pagos no período que não aparecem no extrato são marcados como missing.
+type SettlementImport struct {
+	Gateway  string
+	Format   settlement.Format
+	Filename string
+	From     *time.Time
+	To       *time.Time
+}
+
+// SettlementService concilia os extratos de liquidação dos gateways com os pedidos
+type SettlementService struct {
+	db *gorm.DB
+}
+
+func NewSettlementService(db *gorm.DB) *SettlementService {
+	return &SettlementService{db: db}
+}
+
+// Import lê o extrato, concilia cada linha por transaction_id ou external_ref e grava o relatório
+func (s *SettlementService) Import(ctx context.Context, in SettlementImport, r io.Reader) (*models.SettlementReport, error) {
+	lines, err := settlement.Parse(in.Gateway, in.Format, r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidStatement, err)
+	}
+
+	byTransaction, byRef, err := s.loadOrders(ctx, in.Gateway, lines)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.SettlementReport{
+		Gateway:    in.Gateway,
+		Filename:   in.Filename,
+		Format:     string(in.Format),
+		PeriodFrom: in.From,
+		PeriodTo:   in.To,
+		Lines:      len(lines),
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for _, line := range lines {
+		item := models.SettlementItem{
+			Row:           line.Row,
+			TransactionID: line.TransactionID,
+			ExternalRef:   line.ExternalRef,
+			SettledAmount: line.Amount,
+			SettledFee:    line.Fee,
+			SettledNet:    line.NetAmount,
+		}
+
+		order, ok := byTransaction[line.TransactionID]
+		if !ok {
+			order, ok = byRef[line.ExternalRef]
+		}
+
+		switch {
+		case !ok:
+			item.Kind = models.SettlementExtra
+			report.Extra++
+		case line.Amount != 0 && line.Amount != order.Amount:
+			item.Kind = models.SettlementAmountMismatch
+			report.Mismatched++
+		default:
+			item.Kind = models.SettlementMatched
+			report.Matched++
+		}
+
+		if ok {
+			seen[order.ID] = true
+			item.OrderID = &order.ID
+			item.TransactionID = order.TransactionID
+			item.ExternalRef = order.ExternalRef
+			item.OrderAmount = order.Amount
+			item.OrderStatus = order.Status
+		}
+
+		report.Items = append(report.Items, item)
+	}
+
+	if in.From != nil && in.To != nil {
+		missing, err := s.missingOrders(ctx, in, seen)
+		if err != nil {
+			return nil, err
+		}
+		for _, order := range missing {
+			orderID := order.ID
+			report.Items = append(report.Items, models.SettlementItem{
+				Kind:          models.SettlementMissing,
+				OrderID:       &orderID,
+				TransactionID: order.TransactionID,
+				ExternalRef:   order.ExternalRef,
+				OrderAmount:   order.Amount,
+				OrderStatus:   order.Status,
+			})
+			report.Missing++
+		}
+	}
+
+	if err := s.db.WithContext(ctx).Session(&gorm.Session{CreateBatchSize: settlementLookupChunk}).Create(report).Error; err != nil {
+		return nil, fmt.Errorf("erro ao gravar conciliação: %w", err)
+	}
+
+	log.Printf("🧾 [Settlement] %s: %d linhas, %d conciliadas, %d divergentes, %d extras, %d ausentes",
+		report.Gateway, report.Lines, report.Matched, report.Mismatched, report.Extra, report.Missing)
+
+	return report, nil
+}
+
+// Get devolve o relatório com os itens, opcionalmente filtrados por tipo
+func (s *SettlementService) Get(ctx context.Context, id uuid.UUID, kind string) (*models.SettlementReport, error) {
+	var report models.SettlementReport
+	err := s.db.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB {
+			if kind != "" {
+				db = db.Where("kind = ?", kind)
+			}
+			return db.Order("kind, row")
+		}).
+		First(&report, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// List devolve os relatórios mais recentes (sem itens)
+func (s *SettlementService) List(ctx context.Context, gateway string, limit int) ([]models.SettlementReport, error) {
+	query := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit)
+	if gateway != "" {
+		query = query.Where("gateway = ?", gateway)
+	}
+
+	var reports []models.SettlementReport
+	if err := query.Find(&reports).Error; err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// loadOrders busca os pedidos do gateway citados no extrato, indexados por transaction_id e external_ref
+func (s *SettlementService) loadOrders(ctx context.Context, gateway string, lines []settlement.Line) (map[string]*models.Order, map[string]*models.Order, error) {
+	byTransaction := make(map[string]*models.Order)
+	byRef := make(map[string]*models.Order)
+
+	for start := 0; start < len(lines); start += settlementLookupChunk {
+		end := start + settlementLookupChunk
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		var transactionIDs, refs []string
+		for _, line := range lines[start:end] {
+			if line.TransactionID != "" {
+				transactionIDs = append(transactionIDs, line.TransactionID)
+			}
+			if line.ExternalRef != "" {
+				refs = append(refs, line.ExternalRef)
+			}
+		}
+
+		match := s.db.WithContext(ctx).Where("1 = 0")
+		if len(transactionIDs) > 0 {
+			match = match.Or("transaction_id IN ?", transactionIDs)
+		}
+		if len(refs) > 0 {
+			match = match.Or("external_ref IN ?", refs)
+		}
+
+		var orders []models.Order
+		if err := s.db.WithContext(ctx).
+			Where("gateway = ?", gateway).
+			Where(match).
+			Find(&orders).Error; err != nil {
+			return nil, nil, fmt.Errorf("erro ao buscar pedidos: %w", err)
+		}
+
+		for i := range orders {
+			order := &orders[i]
+			byTransaction[order.TransactionID] = order
+			if order.ExternalRef != "" {
+				byRef[order.ExternalRef] = order
+			}
+		}
+	}
+
+	return byTransaction, byRef, nil
+}
+
+// missingOrders lista os pedidos pagos no período que não apareceram no extrato
+func (s *SettlementService) missingOrders(ctx context.Context, in SettlementImport, seen map[uuid.UUID]bool) ([]models.Order, error) {
+	var orders []models.Order
+	if err := s.db.WithContext(ctx).
+		Where("gateway = ? AND approved_at >= ? AND approved_at < ? AND status IN ?", in.Gateway, in.From, in.To,
+			[]models.OrderStatus{models.OrderStatusApproved, models.OrderStatusPaid, models.OrderStatusPartiallyRefunded}).
+		Order("approved_at").
+		Find(&orders).Error; err != nil {
+		return nil, fmt.Errorf("erro ao buscar pedidos do período: %w", err)
+	}
+
+	missing := orders[:0]
+	for _, order := range orders {
+		if !seen[order.ID] {
+			missing = append(missing, order)
+		}
+	}
+	return missing, nil
+}
@@ -0,0 +1,190 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+)
+
+const (
+	statusPollBatchSize = 200
+	// Status fora do vocabulário do gateway só é consultado de novo depois disso
+	statusPollUnknownBackoff = time.Hour
+)
+
+// StatusPollService consulta no gateway os pedidos pendentes, para recuperar
+// webhooks que nunca chegaram. Mudanças são aplicadas pelo mesmo caminho dos
+// webhooks (WebhookService.ProcessEvent) com origem "polling".
+type StatusPollService struct {
+	db       *gorm.DB
+	webhooks *WebhookService
+	gateways *gateways.Registry
+	cfg      *config.Config
+	rates    map[string]float64
+}
+
+func NewStatusPollService(db *gorm.DB, webhooks *WebhookService, registry *gateways.Registry, cfg *config.Config) *StatusPollService {
+	return &StatusPollService{
+		db:       db,
+		webhooks: webhooks,
+		gateways: registry,
+		cfg:      cfg,
+		rates:    parseRateLimits(cfg.StatusPollRateLimits),
+	}
+}
+
+// PollPending consulta um lote de pedidos pendentes ainda dentro da validade e
+// devolve quantos mudaram de status. Os pedidos consultados há mais tempo vêm primeiro.
+func (s *StatusPollService) PollPending(ctx context.Context) (int, error) {
+	now := time.Now()
+	minAge := time.Duration(s.cfg.StatusPollMinAge) * time.Second
+	interval := time.Duration(s.cfg.StatusPollInterval) * time.Second
+
+	// Reserva o lote marcando status_polled_at: com SKIP LOCKED e o filtro pelo intervalo,
+	// outra instância do worker pega os próximos em vez de consultar os mesmos pedidos
+	var orders []models.Order
+	if err := s.db.WithContext(ctx).Raw(`
+		UPDATE orders SET status_polled_at = ?
+		WHERE id IN (
+			SELECT id FROM orders
+			WHERE status IN ? AND gateway <> '' AND deleted_at IS NULL
+				AND (expires_at IS NULL OR expires_at > ?) AND created_at <= ?
+				AND (status_polled_at IS NULL OR status_polled_at <= ?)
+			ORDER BY status_polled_at NULLS FIRST
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		now, []models.OrderStatus{models.OrderStatusPending, models.OrderStatusWaitingPayment},
+		now, now.Add(-minAge), now.Add(-interval), statusPollBatchSize,
+	).Scan(&orders).Error; err != nil {
+		return 0, err
+	}
+	if len(orders) == 0 {
+		return 0, nil
+	}
+
+	byGateway := make(map[string][]models.Order)
+	for _, order := range orders {
+		byGateway[order.Gateway] = append(byGateway[order.Gateway], order)
+	}
+
+	// Um goroutine por gateway, cada um no próprio ritmo
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		changed int
+	)
+	for name, pending := range byGateway {
+		gateway, ok := s.gateways.Get(name)
+		if !ok {
+			log.Printf("⚠️ [StatusPoll] Gateway %s não registrado, %d pedidos ignorados", name, len(pending))
+			continue
+		}
+
+		wg.Add(1)
+		go func(gateway gateways.PaymentGateway, pending []models.Order) {
+			defer wg.Done()
+			count := s.pollGateway(ctx, gateway, pending)
+
+			mu.Lock()
+			changed += count
+			mu.Unlock()
+		}(gateway, pending)
+	}
+	wg.Wait()
+
+	return changed, nil
+}
+
+func (s *StatusPollService) pollGateway(ctx context.Context, gateway gateways.PaymentGateway, orders []models.Order) int {
+	limiter := time.NewTicker(s.interval(gateway.Name()))
+	defer limiter.Stop()
+
+	changed := 0
+	for i := range orders {
+		if i > 0 {
+			select {
+			case <-ctx.Done():
+				return changed
+			case <-limiter.C:
+			}
+		}
+
+		order := &orders[i]
+		status, err := gateway.QueryStatus(ctx, order.TransactionID)
+		if err != nil && !errors.Is(err, gateways.ErrUnknownStatus) {
+			log.Printf("⚠️ [StatusPoll] Erro ao consultar %s no %s: %v", order.TransactionID, gateway.Platform(), err)
+			continue
+		}
+		if errors.Is(err, gateways.ErrUnknownStatus) {
+			// Não adianta consultar a cada ciclo: adia a próxima consulta deste pedido
+			log.Printf("⚠️ [StatusPoll] Status desconhecido %q para %s no %s, nova consulta em %s", status.RawStatus, order.TransactionID, gateway.Platform(), statusPollUnknownBackoff)
+			if err := s.db.WithContext(ctx).Model(order).
+				UpdateColumn("status_polled_at", time.Now().Add(statusPollUnknownBackoff)).Error; err != nil {
+				log.Printf("❌ [StatusPoll] Erro ao adiar consulta de %s: %v", order.TransactionID, err)
+			}
+			continue
+		}
+		if status.Status == order.Status || status.Status == models.OrderStatusPending {
+			continue
+		}
+
+		updated, err := s.webhooks.processEvent(ctx, &gateways.PaymentEvent{
+			Gateway:       gateway.Name(),
+			Source:        "polling",
+			TransactionID: order.TransactionID,
+			Status:        status.Status,
+			RawStatus:     status.RawStatus,
+			Amount:        status.Amount,
+		})
+		if err != nil {
+			log.Printf("❌ [StatusPoll] Erro ao aplicar status de %s: %v", order.TransactionID, err)
+			continue
+		}
+		if !updated {
+			continue
+		}
+
+		log.Printf("🔎 [StatusPoll] Pedido %s: %s -> %s (%s)", order.ID, order.Status, status.Status, gateway.Platform())
+		changed++
+	}
+
+	return changed
+}
+
+// interval é o tempo mínimo entre duas consultas ao mesmo gateway
+func (s *StatusPollService) interval(gateway string) time.Duration {
+	rate, ok := s.rates[gateway]
+	if !ok {
+		rate = s.cfg.StatusPollRate
+	}
+	if rate <= 0 {
+		rate = 1
+	}
+	return time.Duration(float64(time.Second) / rate)
+}
+
+// parseRateLimits lê "gateway=consultas_por_segundo,..."
+func parseRateLimits(spec string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, part := range strings.Split(spec, ",") {
+		name, rateStr, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		if rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64); err == nil && rate > 0 {
+			rates[strings.ToLower(strings.TrimSpace(name))] = rate
+		}
+	}
+	return rates
+}
@@ -118,11 +118,18 @@ func (s *UtmifyService) buildUtmifyPayload(order *models.Order, status string) *
 		}
 	}
 
+	if order.GatewayFee > 0 {
+		payload.Fee = &dto.UtmifyFee{
+			FixedAmount: order.FeeFixedAmount,
+			NetAmount:   order.UserCommission(),
+		}
+	}
+
 	// Commission
 	payload.Commission = &dto.UtmifyCommission{
 		TotalPrice:     order.Amount,
-		GatewayFee:     0,
-		UserCommission: order.Amount,
+		GatewayFee:     order.GatewayFee,
+		UserCommission: order.UserCommission(),
 	}
 
 	return payload
@@ -1,224 +1,275 @@
 package services
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"log"
-	"net/http"
 	"strings"
-	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/victtorkaiser/server-apis/internal/config"
-	"github.com/victtorkaiser/server-apis/internal/dto"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/gateways"
 	"github.com/victtorkaiser/server-apis/internal/models"
 	"github.com/victtorkaiser/server-apis/internal/queue"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type WebhookService struct {
-	db       *gorm.DB
-	redis    *redis.Client
-	rabbitMQ *queue.RabbitMQ
-	cfg      *config.Config
+	db    *gorm.DB
+	redis *redis.Client
+	bus   queue.EventBus
+	cfg   *config.Config
 }
 
-func NewWebhookService(db *gorm.DB, redis *redis.Client, rabbitMQ *queue.RabbitMQ, cfg *config.Config) *WebhookService {
+func NewWebhookService(db *gorm.DB, redis *redis.Client, bus queue.EventBus, cfg *config.Config) *WebhookService {
 	return &WebhookService{
-		db:       db,
-		redis:    redis,
-		rabbitMQ: rabbitMQ,
-		cfg:      cfg,
+		db:    db,
+		redis: redis,
+		bus:   bus,
+		cfg:   cfg,
 	}
 }
 
-func (s *WebhookService) ProcessWebhook(ctx context.Context, webhook *dto.WebhookPayload) error {
-	paymentCode := webhook.GetPaymentCode()
-	status := webhook.GetStatus()
+// ProcessEvent aplica no pedido um evento já interpretado pelo adapter do gateway
+func (s *WebhookService) ProcessEvent(ctx context.Context, event *gateways.PaymentEvent) error {
+	_, err := s.processEvent(ctx, event)
+	return err
+}
+
+// processEvent aplica o evento e indica se o status do pedido mudou. Evento repetido
+// ou fora de ordem não é erro, mas também não conta como mudança.
+func (s *WebhookService) processEvent(ctx context.Context, event *gateways.PaymentEvent) (bool, error) {
+	paymentCode := event.TransactionID
+	status := event.RawStatus
+	newStatus := event.Status
 
-	log.Printf("🔄 [Webhook] Processando: Type=%s PaymentCode=%s Status=%s", webhook.Type, paymentCode, status)
+	log.Printf("🔄 [Webhook] Processando: Gateway=%s PaymentCode=%s Status=%s (%s)", event.Gateway, paymentCode, newStatus, status)
 
 	// Valida se tem payment code
 	if paymentCode == "" {
-		return fmt.Errorf("payment_code/objectId não encontrado no webhook")
+		return false, fmt.Errorf("payment_code/objectId não encontrado no webhook")
 	}
 
-	// Busca o pedido
-	var order models.Order
-	if err := s.db.Preload("Customer").Preload("TrackingParameter").First(&order, "transaction_id = ?", paymentCode).Error; err != nil {
-		log.Printf("❌ [Webhook] Pedido não encontrado: transaction_id=%s", paymentCode)
-		return fmt.Errorf("pedido não encontrado: %w", err)
+	// Status fora do vocabulário do gateway nunca vira "pending" por omissão
+	if newStatus == "" {
+		return false, fmt.Errorf("%w: %q", gateways.ErrUnknownStatus, status)
 	}
 
-	log.Printf("📦 [Webhook] Pedido encontrado: ID=%s Platform=%s OldStatus=%s", order.ID, order.Platform, order.Status)
+	source := "webhook"
+	if event.Source != "" {
+		source = event.Source
+	}
+	if event.Gateway != "" {
+		source += ":" + event.Gateway
+	}
 
-	// Atualiza o status
-	oldStatus := order.Status
-	newStatus := s.mapStatus(status)
-	order.Status = newStatus
-	order.UpdatedAt = time.Now()
+	var order models.Order
+	var oldStatus models.OrderStatus
+	var statusErr error
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Busca o pedido com lock para serializar webhooks concorrentes
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, "transaction_id = ?", paymentCode).Error; err != nil {
+			log.Printf("❌ [Webhook] Pedido não encontrado: transaction_id=%s", paymentCode)
+			return fmt.Errorf("pedido não encontrado: %w", err)
+		}
 
-	// Se aprovado/pago, marca data de aprovação
-	if newStatus == models.OrderStatusApproved || newStatus == models.OrderStatusPaid {
-		now := time.Now()
-		order.ApprovedAt = &now
-		log.Printf("✅ [Webhook] Pagamento aprovado em: %s", now.Format("2006-01-02 15:04:05"))
+		log.Printf("📦 [Webhook] Pedido encontrado: ID=%s Platform=%s OldStatus=%s", order.ID, order.Platform, order.Status)
+
+		oldStatus = order.Status
+
+		// Tarifa atualizada pelo webhook vale mesmo se o status não mudar
+		if applyGatewayFee(&order, event.Fee) {
+			if err := tx.Model(&order).Updates(gatewayFeeColumns(&order)).Error; err != nil {
+				return fmt.Errorf("erro ao atualizar tarifa: %w", err)
+			}
+		}
+
+		// Status em savepoint: webhook repetido ou fora de ordem desfaz só esta parte,
+		// e a tarifa gravada acima é mantida
+		if err := tx.SavePoint("event_status").Error; err != nil {
+			return err
+		}
+
+		statusErr = s.applyEventStatus(tx, &order, event, oldStatus, source)
+		if errors.Is(statusErr, models.ErrSameStatus) || errors.Is(statusErr, models.ErrInvalidTransition) {
+			return tx.RollbackTo("event_status").Error
+		}
+		return statusErr
+	})
+	if err == nil {
+		err = statusErr
+	}
+
+	switch {
+	case errors.Is(err, models.ErrSameStatus):
+		// Webhook repetido: nada muda (ApprovedAt original é preservado)
+		log.Printf("ℹ️ [Webhook] Pedido %s já está em %s, ignorando", paymentCode, order.Status)
+		return false, nil
+	case errors.Is(err, models.ErrInvalidTransition):
+		// Webhook atrasado ou fora de ordem: mantém o status atual
+		log.Printf("⚠️ [Webhook] Transição ignorada para %s: %v", paymentCode, err)
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+
+	if order.Status == oldStatus {
+		// Ex.: estorno já registrado ou novo estorno parcial: status não muda
+		return false, nil
 	}
 
-	// Salva no banco
-	if err := s.db.Save(&order).Error; err != nil {
-		return fmt.Errorf("erro ao atualizar status: %w", err)
+	log.Printf("✅ [Webhook] Status atualizado: %s -> %s (transaction_id=%s)", oldStatus, order.Status, paymentCode)
+
+	if order.Status.IsPaid() {
+		log.Printf("✅ [Webhook] Pagamento aprovado em: %s", order.ApprovedAt.Format("2006-01-02 15:04:05"))
 	}
 
-	log.Printf("✅ [Webhook] Status atualizado: %s -> %s (transaction_id=%s)", oldStatus, newStatus, paymentCode)
+	return true, nil
+}
+
+// applyEventStatus aplica o status do evento ao pedido travado: estorno, expiração,
+// divergência de valor ou transição comum, com os eventos correspondentes
+func (s *WebhookService) applyEventStatus(tx *gorm.DB, order *models.Order, event *gateways.PaymentEvent, oldStatus models.OrderStatus, source string) error {
+	status := event.RawStatus
+	newStatus := event.Status
 
-	// Publica eventos na fila se aprovado (se RabbitMQ estiver disponível)
-	if newStatus == models.OrderStatusApproved || newStatus == models.OrderStatusPaid {
-		if s.rabbitMQ != nil {
-			log.Printf("📤 [Webhook] Publicando eventos payment.approved e utmify.approved")
+	if newStatus == models.OrderStatusRefunded || newStatus == models.OrderStatusPartiallyRefunded {
+		return s.reconcileRefund(tx, order, event, source)
+	}
 
-			s.rabbitMQ.Publish("payment.approved", map[string]interface{}{
-				"order_id":       order.ID.String(),
-				"transaction_id": order.TransactionID,
-				"status":         string(order.Status),
-				"platform":       order.Platform,
-			})
+	if newStatus == models.OrderStatusExpired {
+		return expireOrder(tx, order, source, status)
+	}
 
-			// Publica para enviar ao Utmify
-			s.rabbitMQ.Publish("utmify.approved", map[string]interface{}{
-				"order_id":       order.ID.String(),
-				"transaction_id": order.TransactionID,
-			})
-		} else {
-			log.Printf("⚠️ [Webhook] RabbitMQ não disponível, eventos não publicados")
+	// Pagamento com valor/moeda divergente vai para revisão em vez de aprovar
+	var discrepancy *models.PaymentDiscrepancy
+	if newStatus.IsPaid() && !order.Status.IsPaid() {
+		discrepancy = checkPaymentAmount(order, event)
+	}
+	if discrepancy != nil {
+		discrepancy.Source = source
+		discrepancy.RawStatus = status
+		if err := tx.Create(discrepancy).Error; err != nil {
+			return fmt.Errorf("erro ao registrar divergência: %w", err)
 		}
 
-		// Envia webhook para URL externa (assíncrono)
-		go s.SendExternalWebhook(&order)
+		log.Printf("🚨 [Webhook] Divergência no pedido %s: %s (esperado %d %s, recebido %d %s)",
+			order.ID, discrepancy.Kind, discrepancy.ExpectedAmount, discrepancy.ExpectedCurrency,
+			discrepancy.ReceivedAmount, discrepancy.ReceivedCurrency)
+
+		newStatus = models.OrderStatusNeedsReview
 	}
 
-	return nil
-}
+	if err := transitionOrder(tx, order, newStatus, source, status); err != nil {
+		return err
+	}
 
-func (s *WebhookService) mapStatus(status string) models.OrderStatus {
-	status = strings.ToUpper(status)
-	switch status {
-	case "APPROVED", "PAID", "AUTHORIZED":
-		return models.OrderStatusApproved
-	case "PENDING":
-		return models.OrderStatusPending
-	case "WAITING_PAYMENT":
-		return models.OrderStatusWaitingPayment
-	case "REFUNDED":
-		return models.OrderStatusRefunded
-	case "CANCELLED":
-		return models.OrderStatusCancelled
-	default:
-		return models.OrderStatusPending
+	eventType, ok := events.ForStatus(newStatus)
+	if !ok {
+		return nil
 	}
-}
 
-// SendExternalWebhook envia webhook para URL externa do cliente
-func (s *WebhookService) SendExternalWebhook(order *models.Order) {
-	if order.WebhookURL == "" {
-		log.Printf("⚠️ [Webhook Externo] Nenhuma URL configurada para order %s", order.ID)
-		return
-	}
-
-	log.Printf("📤 [Webhook Externo] Enviando para: %s", order.WebhookURL)
-
-	// Prepara payload do webhook
-	payload := map[string]interface{}{
-		"event":          "payment.approved",
-		"transaction_id": order.TransactionID,
-		"order_id":       order.ID.String(),
-		"status":         string(order.Status),
-		"amount":         order.Amount,
-		"payment_method": order.PaymentMethod,
-		"platform":       order.Platform,
-		"approved_at":    order.ApprovedAt,
-		"created_at":     order.CreatedAt,
-		"customer": map[string]interface{}{
-			"id":       order.Customer.ID.String(),
-			"name":     order.Customer.Name,
-			"email":    order.Customer.Email,
-			"phone":    order.Customer.Phone,
-			"document": order.Customer.Document,
-		},
-	}
-
-	// Se tiver tracking parameters, inclui
-	if order.TrackingParameter != nil {
-		payload["tracking_params"] = map[string]interface{}{
-			"utm_source":   order.TrackingParameter.UtmSource,
-			"utm_campaign": order.TrackingParameter.UtmCampaign,
-			"utm_medium":   order.TrackingParameter.UtmMedium,
-			"utm_content":  order.TrackingParameter.UtmContent,
-			"utm_term":     order.TrackingParameter.UtmTerm,
-			"gclid":        order.TrackingParameter.Gclid,
-			"fbclid":       order.TrackingParameter.Fbclid,
-			"ttclid":       order.TrackingParameter.Ttclid,
-			"sck":          order.TrackingParameter.Sck,
-			"xcod":         order.TrackingParameter.Xcod,
-		}
+	// Notificação para o WebhookURL do pedido (entregue pelo WebhookDispatcher)
+	if err := enqueueWebhookDelivery(tx, order, eventType, oldStatus); err != nil {
+		return err
 	}
 
-	body, _ := json.Marshal(payload)
-	log.Printf("📦 [Webhook Externo] Payload: %s", string(body))
+	log.Printf("📤 [Webhook] Enfileirando evento %s", eventType)
+
+	if err := enqueueOutbox(tx, eventType, order.ID.String(), events.PaymentStatusPayload{
+		OrderID:        order.ID,
+		TransactionID:  order.TransactionID,
+		PreviousStatus: oldStatus,
+		Status:         order.Status,
+		Platform:       order.Platform,
+		Discrepancy:    discrepancy,
+	}); err != nil {
+		return err
+	}
 
-	// Envia webhook com retry (5 tentativas: imediato, 1s, 10s, 30s, 60s)
-	maxRetries := 5
-	retryIntervals := []time.Duration{
-		1 * time.Second,  // Tentativa 2
-		10 * time.Second, // Tentativa 3
-		30 * time.Second, // Tentativa 4
-		60 * time.Second, // Tentativa 5
+	if !newStatus.IsPaid() {
+		return nil
 	}
 
-	for i := 1; i <= maxRetries; i++ {
-		httpReq, err := http.NewRequest("POST", order.WebhookURL, bytes.NewBuffer(body))
-		if err != nil {
-			log.Printf("❌ [Webhook Externo] Erro ao criar requisição: %v", err)
-			return
-		}
+	// Evento para enviar ao Utmify
+	return enqueueOutbox(tx, events.UtmifyApproved, order.ID.String(), events.UtmifyApprovedPayload{
+		OrderID:       order.ID,
+		TransactionID: order.TransactionID,
+	})
+}
 
-		httpReq.Header.Set("Content-Type", "application/json")
-		httpReq.Header.Set("User-Agent", "Server-APIs-Webhook/1.0")
-		httpReq.Header.Set("X-Webhook-Event", "payment.approved")
-		httpReq.Header.Set("X-Transaction-ID", order.TransactionID)
-
-		client := &http.Client{Timeout: 30 * time.Second}
-		resp, err := client.Do(httpReq)
-		if err != nil {
-			log.Printf("❌ [Webhook Externo] Tentativa %d/%d falhou: %v", i, maxRetries, err)
-			if i < maxRetries {
-				interval := retryIntervals[i-1]
-				log.Printf("⏳ [Webhook Externo] Aguardando %v antes da próxima tentativa...", interval)
-				time.Sleep(interval)
-				continue
-			}
-			return
+// reconcileRefund registra um estorno informado pelo gateway. Estornos pendentes feitos pela
+// API (POST /payments/:id/refund) são confirmados e só então mudam o pedido; o que o gateway
+// informar além deles vira um novo registro.
+func (s *WebhookService) reconcileRefund(tx *gorm.DB, order *models.Order, event *gateways.PaymentEvent, source string) error {
+	var pending []models.Refund
+	if err := tx.Where("order_id = ? AND status = ?", order.ID, models.RefundStatusPending).
+		Order("created_at").Find(&pending).Error; err != nil {
+		return fmt.Errorf("erro ao buscar estornos pendentes: %w", err)
+	}
+
+	// O gateway confirmou: estornos pendentes deste pedido passam a concluídos
+	for i := range pending {
+		if err := confirmRefund(tx, order, &pending[i], source, event.RawStatus); err != nil {
+			return err
 		}
-		defer resp.Body.Close()
+	}
 
-		respBody, _ := io.ReadAll(resp.Body)
-		log.Printf("📡 [Webhook Externo] HTTP %d: %s", resp.StatusCode, string(respBody))
+	amount := unrecordedRefund(order, event, len(pending))
+	if amount <= 0 {
+		// Estorno já registrado (ex.: feito pela API ou webhook repetido): nada além da confirmação
+		log.Printf("ℹ️ [Webhook] Estorno do pedido %s já registrado (%d pendente(s) confirmado(s))", order.ID, len(pending))
+		return nil
+	}
 
-		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
-			log.Printf("✅ [Webhook Externo] Enviado com sucesso para %s", order.WebhookURL)
-			return
-		}
+	_, err := applyRefund(tx, order, refundInput{
+		Amount:    amount,
+		Status:    models.RefundStatusSucceeded,
+		Source:    source,
+		RawStatus: event.RawStatus,
+	})
+	return err
+}
 
-		log.Printf("⚠️ [Webhook Externo] Tentativa %d/%d - HTTP %d", i, maxRetries, resp.StatusCode)
-		if i < maxRetries {
-			interval := retryIntervals[i-1]
-			log.Printf("⏳ [Webhook Externo] Aguardando %v antes da próxima tentativa...", interval)
-			time.Sleep(interval)
-		}
+// unrecordedRefund devolve quanto do estorno informado pelo gateway ainda não está no pedido,
+// depois de confirmados os estornos pendentes. Sem o total estornado no webhook, a confirmação
+// cobre os pendentes; sem pendentes, o estorno é do saldo restante.
+func unrecordedRefund(order *models.Order, event *gateways.PaymentEvent, confirmed int) int {
+	switch {
+	case event.RefundedAmount > 0:
+		return event.RefundedAmount - order.RefundedAmount
+	case confirmed > 0:
+		return 0
+	default:
+		return order.Amount - order.RefundedAmount
+	}
+}
+
+// checkPaymentAmount compara o valor/moeda confirmados pelo gateway com o pedido.
+// Valores e moedas não informados pelo gateway não são verificados.
+func checkPaymentAmount(order *models.Order, event *gateways.PaymentEvent) *models.PaymentDiscrepancy {
+	discrepancy := &models.PaymentDiscrepancy{
+		OrderID:          order.ID,
+		ExpectedAmount:   order.Amount,
+		ReceivedAmount:   event.Amount,
+		ExpectedCurrency: models.OrderCurrency,
+		ReceivedCurrency: strings.ToUpper(event.Currency),
+	}
+
+	switch {
+	case discrepancy.ReceivedCurrency != "" && discrepancy.ReceivedCurrency != models.OrderCurrency:
+		discrepancy.Kind = models.DiscrepancyCurrencyMismatch
+	case event.Amount > 0 && event.Amount < order.Amount:
+		discrepancy.Kind = models.DiscrepancyUnderpayment
+	case event.Amount > order.Amount:
+		discrepancy.Kind = models.DiscrepancyOverpayment
+	default:
+		return nil
 	}
 
-	log.Printf("❌ [Webhook Externo] Todas as tentativas falharam para %s", order.WebhookURL)
+	return discrepancy
 }
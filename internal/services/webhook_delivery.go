@@ -0,0 +1,118 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+)
+
+// enqueueWebhookDelivery grava a notificação para o WebhookURL do pedido, se o endpoint
+// assinar o evento. URL sem endpoint cadastrado recebe só payment.approved, como antes
+// dos endpoints existirem. from é o status anterior à mudança (vazio na criação).
+// Deve ser chamada na mesma transação que alterou o pedido; o WebhookDispatcher faz a entrega.
+func enqueueWebhookDelivery(tx *gorm.DB, order *models.Order, event events.Type, from models.OrderStatus) error {
+	if order.WebhookURL == "" {
+		return nil
+	}
+
+	var endpoint models.WebhookEndpoint
+	err := tx.Where("url = ?", order.WebhookURL).Take(&endpoint).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		if event != events.PaymentApproved {
+			return nil
+		}
+	case err != nil:
+		return fmt.Errorf("erro ao buscar endpoint do webhook: %w", err)
+	case !endpoint.Subscribes(event.String()):
+		return nil
+	}
+
+	_, err = createWebhookDelivery(tx, order, event, from, false)
+	return err
+}
+
+// createWebhookDelivery monta o payload com o estado atual do pedido e grava a entrega
+func createWebhookDelivery(tx *gorm.DB, order *models.Order, event events.Type, from models.OrderStatus, redelivery bool) (*models.WebhookDelivery, error) {
+	// Customer e tracking vão no payload
+	if order.Customer.ID != order.CustomerID {
+		if err := tx.First(&order.Customer, "id = ?", order.CustomerID).Error; err != nil {
+			return nil, fmt.Errorf("erro ao carregar customer do webhook: %w", err)
+		}
+	}
+	if order.TrackingParameterID != nil && order.TrackingParameter == nil {
+		var tp models.TrackingParameter
+		if err := tx.First(&tp, "id = ?", *order.TrackingParameterID).Error; err == nil {
+			order.TrackingParameter = &tp
+		}
+	}
+
+	body, err := json.Marshal(buildWebhookPayload(order, event, from))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao serializar webhook: %w", err)
+	}
+
+	delivery := &models.WebhookDelivery{
+		OrderID:    order.ID,
+		Event:      event.String(),
+		URL:        order.WebhookURL,
+		Payload:    string(body),
+		Redelivery: redelivery,
+	}
+	if err := tx.Create(delivery).Error; err != nil {
+		return nil, fmt.Errorf("erro ao gravar webhook %s: %w", event, err)
+	}
+
+	log.Printf("📥 [Webhook Externo] %s enfileirado para %s (delivery %s)", event, order.WebhookURL, delivery.ID)
+	return delivery, nil
+}
+
+func buildWebhookPayload(order *models.Order, event events.Type, from models.OrderStatus) map[string]interface{} {
+	payload := map[string]interface{}{
+		"event":           event,
+		"transaction_id":  order.TransactionID,
+		"order_id":        order.ID.String(),
+		"previous_status": string(from),
+		"status":          string(order.Status),
+		"amount":          order.Amount,
+		"payment_method":  order.PaymentMethod,
+		"platform":        order.Platform,
+		"approved_at":     order.ApprovedAt,
+		"refunded_at":     order.RefundedAt,
+		"refunded_amount": order.RefundedAmount,
+		"gateway_fee":     order.GatewayFee,
+		"net_amount":      order.NetAmount,
+		"expires_at":      order.ExpiresAt,
+		"created_at":      order.CreatedAt,
+		"customer": map[string]interface{}{
+			"id":       order.Customer.ID.String(),
+			"name":     order.Customer.Name,
+			"email":    order.Customer.Email,
+			"phone":    order.Customer.Phone,
+			"document": order.Customer.Document,
+		},
+	}
+
+	// Se tiver tracking parameters, inclui
+	if order.TrackingParameter != nil {
+		payload["tracking_params"] = map[string]interface{}{
+			"utm_source":   order.TrackingParameter.UtmSource,
+			"utm_campaign": order.TrackingParameter.UtmCampaign,
+			"utm_medium":   order.TrackingParameter.UtmMedium,
+			"utm_content":  order.TrackingParameter.UtmContent,
+			"utm_term":     order.TrackingParameter.UtmTerm,
+			"gclid":        order.TrackingParameter.Gclid,
+			"fbclid":       order.TrackingParameter.Fbclid,
+			"ttclid":       order.TrackingParameter.Ttclid,
+			"sck":          order.TrackingParameter.Sck,
+			"xcod":         order.TrackingParameter.Xcod,
+		}
+	}
+
+	return payload
+}
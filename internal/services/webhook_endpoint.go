@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	// ErrEndpointExists indica webhook_url já cadastrado
+	ErrEndpointExists = errors.New("webhook_url já cadastrado")
+	// ErrInvalidEndpointURL indica URL que não é http(s) absoluta
+	ErrInvalidEndpointURL = errors.New("webhook_url inválido")
+	// ErrInvalidEvent indica evento fora do catálogo na assinatura do endpoint
+	ErrInvalidEvent = errors.New("evento inválido")
+)
+
+// WebhookEndpointService cadastra os webhook_url dos lojistas e seus segredos de assinatura
+type WebhookEndpointService struct {
+	db  *gorm.DB
+	cfg *config.Config
+}
+
+func NewWebhookEndpointService(db *gorm.DB, cfg *config.Config) *WebhookEndpointService {
+	return &WebhookEndpointService{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+// Create cadastra o endpoint e devolve o segredo (só exibido aqui e na rotação)
+func (s *WebhookEndpointService) Create(ctx context.Context, rawURL string, eventNames []string) (*models.WebhookEndpoint, string, error) {
+	subscribed, err := parseSubscription(eventNames)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if u, err := url.Parse(rawURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, "", fmt.Errorf("%w: %s", ErrInvalidEndpointURL, rawURL)
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.WebhookEndpoint{}).Where("url = ?", rawURL).Count(&count).Error; err != nil {
+		return nil, "", err
+	}
+	if count > 0 {
+		return nil, "", ErrEndpointExists
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	endpoint := &models.WebhookEndpoint{URL: rawURL, Secret: secret, Events: subscribed}
+	if err := s.db.WithContext(ctx).Create(endpoint).Error; err != nil {
+		return nil, "", fmt.Errorf("erro ao cadastrar endpoint: %w", err)
+	}
+
+	log.Printf("🔑 [Webhook Endpoint] Cadastrado %s (%s)", endpoint.URL, endpoint.ID)
+	return endpoint, secret, nil
+}
+
+func (s *WebhookEndpointService) List(ctx context.Context) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	if err := s.db.WithContext(ctx).Order("created_at").Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// RotateSecret gera um novo segredo; o atual continua assinando durante WEBHOOK_SECRET_ROTATION_GRACE_HOURS
+func (s *WebhookEndpointService) RotateSecret(ctx context.Context, id uuid.UUID) (*models.WebhookEndpoint, string, error) {
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var endpoint models.WebhookEndpoint
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&endpoint, "id = ?", id).Error; err != nil {
+			return err
+		}
+
+		expiresAt := time.Now().Add(time.Duration(s.cfg.WebhookSecretRotationGrace) * time.Hour)
+		endpoint.PreviousSecret = endpoint.Secret
+		endpoint.PreviousSecretExpiresAt = &expiresAt
+		endpoint.Secret = secret
+
+		return tx.Save(&endpoint).Error
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	log.Printf("🔑 [Webhook Endpoint] Segredo de %s rotacionado (anterior válido até %s)", endpoint.URL, endpoint.PreviousSecretExpiresAt.Format("2006-01-02 15:04:05"))
+	return &endpoint, secret, nil
+}
+
+// UpdateEvents troca a lista de eventos assinados (vazia = todos)
+func (s *WebhookEndpointService) UpdateEvents(ctx context.Context, id uuid.UUID, eventNames []string) (*models.WebhookEndpoint, error) {
+	subscribed, err := parseSubscription(eventNames)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoint models.WebhookEndpoint
+	if err := s.db.WithContext(ctx).First(&endpoint, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+
+	endpoint.Events = subscribed
+	if err := s.db.WithContext(ctx).Save(&endpoint).Error; err != nil {
+		return nil, fmt.Errorf("erro ao atualizar eventos: %w", err)
+	}
+	return &endpoint, nil
+}
+
+func parseSubscription(names []string) ([]string, error) {
+	types, err := events.ParseTypes(names)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEvent, err)
+	}
+
+	subscribed := make([]string, len(types))
+	for i, t := range types {
+		subscribed[i] = t.String()
+	}
+	return subscribed, nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("erro ao gerar segredo: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+)
+
+// ErrNoWebhookURL indica pedido criado sem webhook_url
+var ErrNoWebhookURL = errors.New("pedido sem webhook_url")
+
+// WebhookLogService consulta e reenvia os webhooks de um pedido
+type WebhookLogService struct {
+	db *gorm.DB
+}
+
+func NewWebhookLogService(db *gorm.DB) *WebhookLogService {
+	return &WebhookLogService{db: db}
+}
+
+// ListDeliveries devolve as entregas do pedido com todas as tentativas
+func (s *WebhookLogService) ListDeliveries(ctx context.Context, orderID uuid.UUID) ([]models.WebhookDelivery, error) {
+	var order models.Order
+	if err := s.db.WithContext(ctx).Select("id").First(&order, "id = ?", orderID).Error; err != nil {
+		return nil, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	if err := s.db.WithContext(ctx).
+		Preload("AttemptLog", func(db *gorm.DB) *gorm.DB {
+			return db.Order("attempt")
+		}).
+		Where("order_id = ?", orderID).
+		Order("created_at").
+		Find(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Redeliver enfileira uma nova entrega com o estado atual do pedido.
+// Ignora a lista de eventos assinados: o reenvio é pedido explicitamente.
+func (s *WebhookLogService) Redeliver(ctx context.Context, orderID uuid.UUID) (*models.WebhookDelivery, error) {
+	var delivery *models.WebhookDelivery
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var order models.Order
+		if err := tx.First(&order, "id = ?", orderID).Error; err != nil {
+			return err
+		}
+		if order.WebhookURL == "" {
+			return ErrNoWebhookURL
+		}
+
+		event, ok := events.ForStatus(order.Status)
+		if !ok {
+			event = events.PaymentCreated
+		}
+
+		var err error
+		delivery, err = createWebhookDelivery(tx, &order, event, "", true)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao reenviar webhook: %w", err)
+	}
+
+	return delivery, nil
+}
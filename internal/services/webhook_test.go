@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/victtorkaiser/server-apis/internal/gateways"
+	"github.com/victtorkaiser/server-apis/internal/models"
+)
+
+func TestCheckPaymentAmount(t *testing.T) {
+	order := &models.Order{Amount: 2790}
+
+	tests := []struct {
+		name     string
+		amount   int
+		currency string
+		want     models.DiscrepancyKind // vazio: sem divergência
+	}{
+		{"valor e moeda conferem", 2790, "BRL", ""},
+		{"moeda em minúsculas", 2790, "brl", ""},
+		{"gateway não informa valor nem moeda", 0, "", ""},
+		{"a menor", 2789, "BRL", models.DiscrepancyUnderpayment},
+		{"a maior", 2791, "", models.DiscrepancyOverpayment},
+		{"outra moeda", 2790, "USD", models.DiscrepancyCurrencyMismatch},
+		{"outra moeda tem prioridade sobre o valor", 100, "USD", models.DiscrepancyCurrencyMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkPaymentAmount(order, &gateways.PaymentEvent{Amount: tt.amount, Currency: tt.currency})
+			if tt.want == "" {
+				if got != nil {
+					t.Fatalf("checkPaymentAmount() = %+v, esperado nil", got)
+				}
+				return
+			}
+			if got == nil || got.Kind != tt.want {
+				t.Fatalf("checkPaymentAmount() = %+v, esperado %s", got, tt.want)
+			}
+			if got.ExpectedAmount != 2790 || got.ReceivedAmount != tt.amount || got.ExpectedCurrency != models.OrderCurrency {
+				t.Errorf("divergência = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,306 @@
+// Package settlement lê os extratos de liquidação dos gateways (CSV ou JSON)
+// em linhas normalizadas para a conciliação com os pedidos.
+package settlement
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+var (
+	// ErrUnknownFormat indica formato de arquivo diferente de csv/json
+	ErrUnknownFormat = errors.New("formato de extrato desconhecido")
+	// ErrMissingColumn indica extrato sem coluna de identificação da transação
+	ErrMissingColumn = errors.New("coluna obrigatória ausente no extrato")
+)
+
+// Line é uma linha do extrato com valores em centavos
+type Line struct {
+	Row           int // linha no arquivo (1 = primeira linha de dados)
+	TransactionID string
+	ExternalRef   string
+	Amount        int
+	Fee           int
+	NetAmount     int
+}
+
+// Layout são os nomes de coluna usados pelo extrato de cada gateway
+type Layout struct {
+	TransactionID []string
+	ExternalRef   []string
+	Amount        []string
+	Fee           []string
+	NetAmount     []string
+	AmountInReais bool // valores em reais (ex: 12.34) em vez de centavos
+}
+
+// Layouts indexados pelo nome do gateway no registry
+var Layouts = map[string]Layout{
+	"blupay": {
+		TransactionID: []string{"id", "transaction_id", "transactionId"},
+		ExternalRef:   []string{"externalRef", "external_ref"},
+		Amount:        []string{"amount"},
+		Fee:           []string{"fee", "estimatedFee"},
+		NetAmount:     []string{"netAmount", "net_amount"},
+	},
+	"quantumpay": {
+		TransactionID: []string{"id", "transaction_id", "objectId"},
+		ExternalRef:   []string{"externalRef", "external_ref"},
+		Amount:        []string{"amount"},
+		Fee:           []string{"fee", "fee_amount"},
+		NetAmount:     []string{"netAmount", "net_amount"},
+	},
+	"mangofy": {
+		TransactionID: []string{"payment_code", "transaction_id"},
+		ExternalRef:   []string{"external_code", "external_ref"},
+		Amount:        []string{"amount", "payment_amount"},
+		Fee:           []string{"fee"},
+		NetAmount:     []string{"net_amount"},
+	},
+	"genesys": {
+		TransactionID: []string{"id", "transaction_id"},
+		ExternalRef:   []string{"external_id", "external_ref"},
+		Amount:        []string{"total_amount", "amount"},
+		Fee:           []string{"fee", "fee_amount"},
+		NetAmount:     []string{"net_amount"},
+		AmountInReais: true,
+	},
+}
+
+// LayoutFor devolve o layout do gateway (ou o da BluPay, que usa nomes genéricos)
+func LayoutFor(gateway string) Layout {
+	if layout, ok := Layouts[strings.ToLower(gateway)]; ok {
+		return layout
+	}
+	return Layouts["blupay"]
+}
+
+// FormatFromFilename deduz o formato pela extensão do arquivo
+func FormatFromFilename(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("%w: %s", ErrUnknownFormat, name)
+	}
+}
+
+// Parse lê o extrato no formato informado usando o layout do gateway
+func Parse(gateway string, format Format, r io.Reader) ([]Line, error) {
+	var (
+		records []map[string]string
+		err     error
+	)
+
+	switch Format(strings.ToLower(string(format))) {
+	case FormatCSV:
+		records, err = readCSV(r)
+	case FormatJSON:
+		records, err = readJSON(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	layout := LayoutFor(gateway)
+	lines := make([]Line, 0, len(records))
+	for i, record := range records {
+		line, err := layout.line(record)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", i+1, err)
+		}
+		line.Row = i + 1
+		lines = append(lines, line)
+	}
+
+	return lines, nil
+}
+
+func (l Layout) line(record map[string]string) (Line, error) {
+	line := Line{
+		TransactionID: lookup(record, l.TransactionID),
+		ExternalRef:   lookup(record, l.ExternalRef),
+	}
+	if line.TransactionID == "" && line.ExternalRef == "" {
+		return line, fmt.Errorf("%w: %s ou %s", ErrMissingColumn, strings.Join(l.TransactionID, "/"), strings.Join(l.ExternalRef, "/"))
+	}
+
+	var err error
+	if line.Amount, err = l.cents(lookup(record, l.Amount)); err != nil {
+		return line, fmt.Errorf("valor inválido: %w", err)
+	}
+	if line.Fee, err = l.cents(lookup(record, l.Fee)); err != nil {
+		return line, fmt.Errorf("tarifa inválida: %w", err)
+	}
+	if line.NetAmount, err = l.cents(lookup(record, l.NetAmount)); err != nil {
+		return line, fmt.Errorf("valor líquido inválido: %w", err)
+	}
+
+	return line, nil
+}
+
+// cents converte o valor do extrato para centavos (aceita vírgula decimal)
+func (l Layout) cents(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+
+	if !l.AmountInReais {
+		return strconv.Atoi(value)
+	}
+
+	reais, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0, err
+	}
+	return int(math.Round(reais * 100)), nil
+}
+
+func lookup(record map[string]string, names []string) string {
+	for _, name := range names {
+		if value, ok := record[strings.ToLower(name)]; ok && value != "" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func readCSV(r io.Reader) ([]map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler cabeçalho do CSV: %w", err)
+	}
+	for i := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+	}
+
+	var records []map[string]string
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler CSV: %w", err)
+		}
+
+		record := make(map[string]string, len(header))
+		for i, value := range row {
+			if i < len(header) {
+				record[header[i]] = value
+			}
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+// readJSON aceita uma lista de objetos ou um objeto com a lista em "data"/"items"
+func readJSON(r io.Reader) ([]map[string]string, error) {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []map[string]interface{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		var wrapped struct {
+			Data  []map[string]interface{} `json:"data"`
+			Items []map[string]interface{} `json:"items"`
+		}
+		if err := json.Unmarshal(body, &wrapped); err != nil {
+			return nil, fmt.Errorf("erro ao ler JSON: %w", err)
+		}
+		items = wrapped.Data
+		if items == nil {
+			items = wrapped.Items
+		}
+	}
+
+	records := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		record := make(map[string]string, len(item))
+		for key, value := range item {
+			record[strings.ToLower(key)] = stringify(value)
+		}
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func stringify(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// ParsePeriod lê from/to (YYYY-MM-DD ou RFC3339); to em data simples inclui o dia inteiro
+func ParsePeriod(fromStr, toStr string) (*time.Time, *time.Time, error) {
+	if fromStr == "" && toStr == "" {
+		return nil, nil, nil
+	}
+	if fromStr == "" || toStr == "" {
+		return nil, nil, errors.New("informe from e to juntos")
+	}
+
+	from, _, err := parseDate(fromStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, dateOnly, err := parseDate(toStr)
+	if err != nil {
+		return nil, nil, err
+	}
+	if dateOnly {
+		to = to.AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return nil, nil, errors.New("to deve ser depois de from")
+	}
+
+	return &from, &to, nil
+}
+
+func parseDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, errors.New("data inválida (use YYYY-MM-DD ou RFC3339): " + value)
+	}
+	return t, false, nil
+}
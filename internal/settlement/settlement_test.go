@@ -0,0 +1,179 @@
+package settlement
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		gateway string
+		format  Format
+		input   string
+		want    []Line
+		wantErr bool
+		errIs   error
+	}{
+		{
+			name:    "CSV da BluPay em centavos",
+			gateway: "blupay",
+			format:  FormatCSV,
+			input:   "\ufeffId, externalRef, amount, fee, netAmount\ntx1, ref1, 2790, 100, 2690\ntx2,,1000,,\n",
+			want: []Line{
+				{Row: 1, TransactionID: "tx1", ExternalRef: "ref1", Amount: 2790, Fee: 100, NetAmount: 2690},
+				{Row: 2, TransactionID: "tx2", Amount: 1000},
+			},
+		},
+		{
+			name:    "CSV da Genesys em reais com vírgula",
+			gateway: "genesys",
+			format:  "CSV",
+			input:   "id,external_id,total_amount,fee_amount,net_amount\ngn1,ref1,\"27,90\",\"0,99\",26.91\n",
+			want:    []Line{{Row: 1, TransactionID: "gn1", ExternalRef: "ref1", Amount: 2790, Fee: 99, NetAmount: 2691}},
+		},
+		{
+			name:    "CSV só com cabeçalho",
+			gateway: "blupay",
+			format:  FormatCSV,
+			input:   "id,amount\n",
+			want:    []Line{},
+		},
+		{
+			name:    "CSV sem coluna de identificação",
+			gateway: "blupay",
+			format:  FormatCSV,
+			input:   "amount,fee\n100,1\n",
+			wantErr: true,
+			errIs:   ErrMissingColumn,
+		},
+		{
+			name:    "CSV vazio",
+			gateway: "blupay",
+			format:  FormatCSV,
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "JSON em lista",
+			gateway: "mangofy",
+			format:  FormatJSON,
+			input:   `[{"payment_code":"mg1","external_code":"ref1","payment_amount":2790,"fee":"99","net_amount":2691}]`,
+			want:    []Line{{Row: 1, TransactionID: "mg1", ExternalRef: "ref1", Amount: 2790, Fee: 99, NetAmount: 2691}},
+		},
+		{
+			name:    "JSON em data com valores em reais",
+			gateway: "genesys",
+			format:  FormatJSON,
+			input:   `{"data":[{"ID":"gn1","total_amount":27.9,"fee_amount":"0,99","net_amount":26.91}]}`,
+			want:    []Line{{Row: 1, TransactionID: "gn1", Amount: 2790, Fee: 99, NetAmount: 2691}},
+		},
+		{
+			name:    "JSON em items com gateway desconhecido usa o layout da BluPay",
+			gateway: "outro",
+			format:  FormatJSON,
+			input:   `{"items":[{"externalRef":"ref9","amount":500}]}`,
+			want:    []Line{{Row: 1, ExternalRef: "ref9", Amount: 500}},
+		},
+		{
+			name:    "valor em reais num layout em centavos",
+			gateway: "blupay",
+			format:  FormatJSON,
+			input:   `[{"id":"tx1","amount":"12.34"}]`,
+			wantErr: true,
+		},
+		{
+			name:    "JSON inválido",
+			gateway: "blupay",
+			format:  FormatJSON,
+			input:   `{"data":`,
+			wantErr: true,
+		},
+		{
+			name:    "formato desconhecido",
+			gateway: "blupay",
+			format:  "xml",
+			wantErr: true,
+			errIs:   ErrUnknownFormat,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.gateway, tt.format, strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() erro = %v, esperado erro %v", err, tt.wantErr)
+			}
+			if tt.errIs != nil && !errors.Is(err, tt.errIs) {
+				t.Fatalf("Parse() = %v, esperado %v", err, tt.errIs)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %+v, esperado %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFromFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		want Format
+		err  error
+	}{
+		{"extrato.csv", FormatCSV, nil},
+		{"EXTRATO.JSON", FormatJSON, nil},
+		{"extrato.xlsx", "", ErrUnknownFormat},
+		{"extrato", "", ErrUnknownFormat},
+	}
+
+	for _, tt := range tests {
+		got, err := FormatFromFilename(tt.name)
+		if got != tt.want || !errors.Is(err, tt.err) {
+			t.Errorf("FormatFromFilename(%q) = %q, %v; esperado %q, %v", tt.name, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		wantFrom string
+		wantTo   string
+		wantErr  bool
+	}{
+		{"sem período", "", "", "", "", false},
+		{"datas simples incluem o dia final", "2026-10-01", "2026-10-31", "2026-10-01T00:00:00Z", "2026-11-01T00:00:00Z", false},
+		{"RFC3339", "2026-10-01T12:00:00Z", "2026-10-01T18:00:00Z", "2026-10-01T12:00:00Z", "2026-10-01T18:00:00Z", false},
+		{"só from", "2026-10-01", "", "", "", true},
+		{"data inválida", "01/10/2026", "2026-10-31", "", "", true},
+		{"to antes de from", "2026-10-31T00:00:00Z", "2026-10-01T00:00:00Z", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := ParsePeriod(tt.from, tt.to)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParsePeriod() erro = %v, esperado erro %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if tt.wantFrom == "" {
+				if from != nil || to != nil {
+					t.Errorf("ParsePeriod() = %v, %v; esperado nil", from, to)
+				}
+				return
+			}
+			if got := from.Format(time.RFC3339); got != tt.wantFrom {
+				t.Errorf("from = %s, esperado %s", got, tt.wantFrom)
+			}
+			if got := to.Format(time.RFC3339); got != tt.wantTo {
+				t.Errorf("to = %s, esperado %s", got, tt.wantTo)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package workers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Intervalo da limpeza dos eventos já processados
+const processedEventPruneInterval = time.Hour
+
+// envelopeHandler adapta um handler de envelope ao barramento: confere o tipo,
+// ignora eventos que o consumer já processou e grava o event_id depois do sucesso.
+// Mensagens antigas sem envelope (sem event_id) são processadas sem deduplicação.
+func envelopeHandler(db *gorm.DB, consumer string, eventType events.Type, handle func(*events.Envelope) error) func([]byte) error {
+	return func(data []byte) error {
+		envelope, err := events.ParseEnvelope(data)
+		if err != nil {
+			log.Printf("❌ [%s] Mensagem inválida: %v", consumer, err)
+			return err
+		}
+
+		if envelope.EventID == "" {
+			return handle(envelope)
+		}
+
+		if envelope.Type != eventType {
+			return fmt.Errorf("evento %s inesperado em %s", envelope.Type, consumer)
+		}
+
+		var processed int64
+		if err := db.Model(&models.ProcessedEvent{}).
+			Where("consumer = ? AND event_id = ?", consumer, envelope.EventID).
+			Count(&processed).Error; err != nil {
+			return err
+		}
+		if processed > 0 {
+			log.Printf("ℹ️ [%s] Evento %s já processado, ignorando", consumer, envelope.EventID)
+			return nil
+		}
+
+		if err := handle(envelope); err != nil {
+			return err
+		}
+
+		// Falha aqui não devolve erro: reprocessar reenviaria algo que já deu certo
+		if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&models.ProcessedEvent{
+			Consumer:    consumer,
+			EventID:     envelope.EventID,
+			Type:        envelope.Type.String(),
+			ProcessedAt: time.Now(),
+		}).Error; err != nil {
+			log.Printf("⚠️ [%s] Erro ao registrar evento %s como processado: %v", consumer, envelope.EventID, err)
+		}
+
+		return nil
+	}
+}
+
+// startProcessedEventPruner apaga periodicamente os registros de deduplicação mais
+// antigos que retention; com retention <= 0 nada é apagado
+func startProcessedEventPruner(db *gorm.DB, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(processedEventPruneInterval)
+		defer ticker.Stop()
+
+		for {
+			pruneProcessedEvents(db, retention)
+			<-ticker.C
+		}
+	}()
+}
+
+func pruneProcessedEvents(db *gorm.DB, retention time.Duration) {
+	result := db.Where("processed_at < ?", time.Now().Add(-retention)).Delete(&models.ProcessedEvent{})
+	if result.Error != nil {
+		log.Printf("❌ [EventBus] Erro ao apagar eventos processados: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("🧹 [EventBus] %d eventos processados há mais de %s apagados", result.RowsAffected, retention)
+	}
+}
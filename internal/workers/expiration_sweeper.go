@@ -0,0 +1,55 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/services"
+)
+
+// ExpirationSweeper expira periodicamente os pedidos pendentes cujo PIX já venceu
+type ExpirationSweeper struct {
+	service *services.ExpirationService
+	cfg     *config.Config
+}
+
+func NewExpirationSweeper(service *services.ExpirationService, cfg *config.Config) *ExpirationSweeper {
+	return &ExpirationSweeper{
+		service: service,
+		cfg:     cfg,
+	}
+}
+
+func (w *ExpirationSweeper) Start() {
+	interval := time.Duration(w.cfg.ExpirationSweepInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	log.Printf("🚀 Iniciando ExpirationSweeper (intervalo %s)", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			w.sweep()
+		}
+	}()
+}
+
+// sweep processa lotes até não sobrar pedido vencido
+func (w *ExpirationSweeper) sweep() {
+	for {
+		count, err := w.service.ExpireOverdue(context.Background())
+		if err != nil {
+			log.Printf("❌ [Expiration] Erro ao expirar pedidos: %v", err)
+			return
+		}
+		if count == 0 {
+			return
+		}
+	}
+}
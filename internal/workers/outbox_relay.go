@@ -0,0 +1,180 @@
+package workers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/events"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"github.com/victtorkaiser/server-apis/internal/queue"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	outboxBatchSize  = 100
+	outboxMaxBackoff = 5 * time.Minute
+	// Reserva do lote: cobre o pior caso de todos os Publish esperando o timeout
+	outboxLease = outboxBatchSize*queue.PublishTimeout + time.Minute
+	// Intervalo da limpeza dos eventos já publicados
+	outboxPruneInterval = time.Hour
+)
+
+// OutboxRelay publica no barramento de eventos os eventos gravados na tabela outbox.
+// Um evento só é marcado como publicado depois da confirmação do barramento, então pode ser entregue mais de uma vez.
+type OutboxRelay struct {
+	db  *gorm.DB
+	bus queue.EventBus
+	cfg *config.Config
+}
+
+func NewOutboxRelay(db *gorm.DB, bus queue.EventBus, cfg *config.Config) *OutboxRelay {
+	return &OutboxRelay{
+		db:  db,
+		bus: bus,
+		cfg: cfg,
+	}
+}
+
+func (r *OutboxRelay) Start() {
+	interval := time.Duration(r.cfg.OutboxPollInterval) * time.Second
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	log.Printf("🚀 Iniciando OutboxRelay (intervalo %s)", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := r.relayBatch(); err != nil {
+				log.Printf("❌ [Outbox] Erro ao processar lote: %v", err)
+			}
+		}
+	}()
+
+	if r.cfg.OutboxRetention > 0 {
+		go func() {
+			ticker := time.NewTicker(outboxPruneInterval)
+			defer ticker.Stop()
+
+			for {
+				r.prunePublished()
+				<-ticker.C
+			}
+		}()
+	}
+}
+
+// prunePublished apaga os eventos publicados há mais de OutboxRetention horas.
+// Eventos failed ficam para análise.
+func (r *OutboxRelay) prunePublished() {
+	retention := time.Duration(r.cfg.OutboxRetention) * time.Hour
+
+	result := r.db.Where("status = ? AND published_at < ?", models.OutboxStatusPublished, time.Now().Add(-retention)).
+		Delete(&models.OutboxEvent{})
+	if result.Error != nil {
+		log.Printf("❌ [Outbox] Erro ao apagar eventos publicados: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("🧹 [Outbox] %d eventos publicados há mais de %s apagados", result.RowsAffected, retention)
+	}
+}
+
+func (r *OutboxRelay) relayBatch() error {
+	pending, err := r.claimBatch()
+	if err != nil {
+		return err
+	}
+
+	for i := range pending {
+		event := &pending[i]
+		now := time.Now()
+
+		ctx, cancel := context.WithTimeout(context.Background(), queue.PublishTimeout)
+		err := r.bus.PublishWithContext(ctx, event.Queue, outboxMessage(event))
+		cancel()
+
+		if errors.Is(err, queue.ErrUnroutable) {
+			// Sem fila de destino não adianta retentar: fica como failed para análise
+			event.Attempts++
+			event.Status = models.OutboxStatusFailed
+			event.LastError = err.Error()
+			log.Printf("❌ [Outbox] Evento %s (%s) sem fila de destino, marcado como failed: %v", event.Queue, event.ID, err)
+		} else if err != nil {
+			event.Attempts++
+			event.LastError = err.Error()
+			event.AvailableAt = now.Add(outboxBackoff(event.Attempts))
+			log.Printf("⚠️ [Outbox] Falha ao publicar %s (%s) tentativa %d: %v", event.Queue, event.ID, event.Attempts, err)
+		} else {
+			event.Attempts++
+			event.Status = models.OutboxStatusPublished
+			event.PublishedAt = &now
+			event.LastError = ""
+		}
+
+		if err := r.db.Save(event).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// claimBatch reserva um lote empurrando available_at para frente e confirma a transação
+// antes de publicar: as travas não ficam presas durante o Publish. Se o processo cair,
+// o lote volta a ficar disponível após outboxLease.
+func (r *OutboxRelay) claimBatch() ([]models.OutboxEvent, error) {
+	var pending []models.OutboxEvent
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// SKIP LOCKED permite várias instâncias rodando o relay ao mesmo tempo
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND available_at <= ?", models.OutboxStatusPending, time.Now()).
+			Order("created_at").
+			Limit(outboxBatchSize).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+
+		if len(pending) == 0 {
+			return nil
+		}
+
+		ids := make([]interface{}, len(pending))
+		for i := range pending {
+			ids[i] = pending[i].ID
+		}
+		return tx.Model(&models.OutboxEvent{}).
+			Where("id IN ?", ids).
+			Update("available_at", time.Now().Add(outboxLease)).Error
+	})
+
+	return pending, err
+}
+
+// outboxBackoff dobra a espera a cada tentativa, até outboxMaxBackoff
+func outboxBackoff(attempts int) time.Duration {
+	backoff := time.Second << uint(min(attempts, 10))
+	if backoff > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return backoff
+}
+
+// outboxMessage devolve o envelope do evento, para o barramento levar os metadados
+// nos headers; eventos gravados antes do envelope seguem como JSON puro
+func outboxMessage(event *models.OutboxEvent) interface{} {
+	envelope, err := events.ParseEnvelope([]byte(event.Payload))
+	if err != nil || envelope.EventID == "" {
+		return json.RawMessage(event.Payload)
+	}
+	return envelope
+}
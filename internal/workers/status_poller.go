@@ -0,0 +1,49 @@
+package workers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/services"
+)
+
+// StatusPoller consulta periodicamente no gateway os pedidos ainda pendentes
+type StatusPoller struct {
+	service *services.StatusPollService
+	cfg     *config.Config
+}
+
+func NewStatusPoller(service *services.StatusPollService, cfg *config.Config) *StatusPoller {
+	return &StatusPoller{
+		service: service,
+		cfg:     cfg,
+	}
+}
+
+func (w *StatusPoller) Start() {
+	interval := time.Duration(w.cfg.StatusPollInterval) * time.Second
+	if interval <= 0 {
+		log.Println("ℹ️ StatusPoller desabilitado (STATUS_POLL_INTERVAL=0)")
+		return
+	}
+
+	log.Printf("🚀 Iniciando StatusPoller (intervalo %s)", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			changed, err := w.service.PollPending(context.Background())
+			if err != nil {
+				log.Printf("❌ [StatusPoll] Erro ao consultar pedidos pendentes: %v", err)
+				continue
+			}
+			if changed > 0 {
+				log.Printf("✅ [StatusPoll] %d pedidos atualizados por consulta ao gateway", changed)
+			}
+		}
+	}()
+}
@@ -10,102 +10,97 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/events"
 	"github.com/victtorkaiser/server-apis/internal/models"
 	"github.com/victtorkaiser/server-apis/internal/queue"
 	"gorm.io/gorm"
 )
 
 type UtmifyConsumer struct {
-	db       *gorm.DB
-	rabbitMQ *queue.RabbitMQ
-	cfg      *config.Config
+	db  *gorm.DB
+	bus queue.EventBus
+	cfg *config.Config
 }
 
-func NewUtmifyConsumer(db *gorm.DB, rabbitMQ *queue.RabbitMQ, cfg *config.Config) *UtmifyConsumer {
+func NewUtmifyConsumer(db *gorm.DB, bus queue.EventBus, cfg *config.Config) *UtmifyConsumer {
 	return &UtmifyConsumer{
-		db:       db,
-		rabbitMQ: rabbitMQ,
-		cfg:      cfg,
+		db:  db,
+		bus: bus,
+		cfg: cfg,
 	}
 }
 
 func (c *UtmifyConsumer) Start() error {
-	log.Println("🚀 Iniciando UtmifyConsumer - processando filas utmify.pending e utmify.approved")
+	log.Printf("🚀 Iniciando UtmifyConsumer (%s) - processando filas utmify.pending e utmify.approved", c.bus.Name())
 
 	// Consumer para utmify.pending
-	if err := c.rabbitMQ.Consume("utmify.pending", c.handlePendingOrder); err != nil {
+	if err := c.bus.Consume(events.UtmifyPending.String(), envelopeHandler(c.db, "utmify:pending", events.UtmifyPending, c.handlePendingOrder)); err != nil {
 		return err
 	}
 
 	// Consumer para utmify.approved
-	if err := c.rabbitMQ.Consume("utmify.approved", c.handleApprovedOrder); err != nil {
+	if err := c.bus.Consume(events.UtmifyApproved.String(), envelopeHandler(c.db, "utmify:approved", events.UtmifyApproved, c.handleApprovedOrder)); err != nil {
 		return err
 	}
 
+	startProcessedEventPruner(c.db, time.Duration(c.cfg.ProcessedEventRetention)*time.Hour)
+
 	log.Println("✅ UtmifyConsumer iniciado com sucesso")
 	return nil
 }
 
-func (c *UtmifyConsumer) handlePendingOrder(data []byte) error {
-	var message map[string]interface{}
-	if err := json.Unmarshal(data, &message); err != nil {
+func (c *UtmifyConsumer) handlePendingOrder(envelope *events.Envelope) error {
+	var payload events.UtmifyPendingPayload
+	if err := envelope.Decode(&payload); err != nil {
 		log.Printf("❌ Erro ao decodificar mensagem utmify.pending: %v", err)
 		return err
 	}
 
-	orderIDStr, ok := message["order_id"].(string)
-	if !ok {
-		log.Printf("❌ order_id inválido na mensagem: %v", message)
+	if payload.OrderID == uuid.Nil {
+		log.Printf("❌ order_id ausente na mensagem utmify.pending %s", envelope.EventID)
 		return nil // Não reprocessa
 	}
 
-	orderID, err := uuid.Parse(orderIDStr)
+	order, err := c.loadOrder(payload.OrderID)
 	if err != nil {
-		log.Printf("❌ Erro ao parsear order_id: %v", err)
-		return nil
-	}
-
-	// Busca order completa no banco
-	var order models.Order
-	if err := c.db.Preload("Customer").Preload("TrackingParameter").Preload("Products").First(&order, "id = ?", orderID).Error; err != nil {
-		log.Printf("❌ Erro ao buscar order %s: %v", orderID, err)
 		return err
 	}
 
 	// Envia para Utmify
 	log.Printf("📤 Enviando order %s para Utmify (pending)", order.TransactionID)
-	return c.sendToUtmify(&order, "waiting_payment")
+	return c.sendToUtmify(order, "waiting_payment")
 }
 
-func (c *UtmifyConsumer) handleApprovedOrder(data []byte) error {
-	var message map[string]interface{}
-	if err := json.Unmarshal(data, &message); err != nil {
+func (c *UtmifyConsumer) handleApprovedOrder(envelope *events.Envelope) error {
+	var payload events.UtmifyApprovedPayload
+	if err := envelope.Decode(&payload); err != nil {
 		log.Printf("❌ Erro ao decodificar mensagem utmify.approved: %v", err)
 		return err
 	}
 
-	orderIDStr, ok := message["order_id"].(string)
-	if !ok {
-		log.Printf("❌ order_id inválido na mensagem: %v", message)
+	if payload.OrderID == uuid.Nil {
+		log.Printf("❌ order_id ausente na mensagem utmify.approved %s", envelope.EventID)
 		return nil
 	}
 
-	orderID, err := uuid.Parse(orderIDStr)
+	order, err := c.loadOrder(payload.OrderID)
 	if err != nil {
-		log.Printf("❌ Erro ao parsear order_id: %v", err)
-		return nil
+		return err
 	}
 
-	// Busca order completa no banco
+	// Envia para Utmify
+	log.Printf("📤 Enviando order %s para Utmify (approved)", order.TransactionID)
+	return c.sendToUtmify(order, "paid")
+}
+
+// loadOrder busca a order completa no banco
+func (c *UtmifyConsumer) loadOrder(orderID uuid.UUID) (*models.Order, error) {
 	var order models.Order
 	if err := c.db.Preload("Customer").Preload("TrackingParameter").Preload("Products").First(&order, "id = ?", orderID).Error; err != nil {
 		log.Printf("❌ Erro ao buscar order %s: %v", orderID, err)
-		return err
+		return nil, err
 	}
-
-	// Envia para Utmify
-	log.Printf("📤 Enviando order %s para Utmify (approved)", order.TransactionID)
-	return c.sendToUtmify(&order, "paid")
+	return &order, nil
 }
 
 func (c *UtmifyConsumer) sendToUtmify(order *models.Order, status string) error {
@@ -190,8 +185,8 @@ func (c *UtmifyConsumer) sendToUtmify(order *models.Order, status string) error
 		"trackingParameters": trackingParams,
 		"commission": map[string]interface{}{
 			"totalPriceInCents":     order.Amount,
-			"gatewayFeeInCents":     0,
-			"userCommissionInCents": order.Amount,
+			"gatewayFeeInCents":     order.GatewayFee,
+			"userCommissionInCents": order.UserCommission(),
 		},
 		"isTest": false,
 	}
@@ -0,0 +1,245 @@
+package workers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/victtorkaiser/server-apis/internal/config"
+	"github.com/victtorkaiser/server-apis/internal/models"
+	"github.com/victtorkaiser/server-apis/pkg/webhooksig"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	webhookBatchSize    = 50 // entregas por ciclo do ticker
+	webhookPostTimeout  = 30 * time.Second
+	webhookLease        = 2 * time.Minute // reserva de uma entrega enquanto o POST está em andamento (> webhookPostTimeout)
+	webhookBaseBackoff  = 10 * time.Second
+	webhookMaxBackoff   = time.Hour
+	webhookMaxBodyStore = 2048
+)
+
+// WebhookDispatcher entrega as notificações de webhook_deliveries.
+// Sobrevive a restart: o estado de cada entrega fica no banco.
+type WebhookDispatcher struct {
+	db     *gorm.DB
+	cfg    *config.Config
+	client *http.Client
+}
+
+func NewWebhookDispatcher(db *gorm.DB, cfg *config.Config) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		db:     db,
+		cfg:    cfg,
+		client: &http.Client{Timeout: webhookPostTimeout},
+	}
+}
+
+func (d *WebhookDispatcher) Start() {
+	interval := time.Duration(d.cfg.WebhookDeliveryPollInterval) * time.Second
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	log.Printf("🚀 Iniciando WebhookDispatcher (intervalo %s)", interval)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			// Uma entrega por vez: a reserva só precisa cobrir um POST
+			for range webhookBatchSize {
+				delivery, err := d.claimNext()
+				if err != nil {
+					log.Printf("❌ [Webhook Externo] Erro ao buscar entregas: %v", err)
+					break
+				}
+				if delivery == nil {
+					break
+				}
+
+				d.deliver(delivery)
+			}
+		}
+	}()
+}
+
+// claimNext reserva a próxima entrega vencida empurrando next_attempt_at para frente;
+// se o processo cair no meio do POST, a entrega volta a ficar disponível após webhookLease
+func (d *WebhookDispatcher) claimNext() (*models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := d.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, time.Now()).
+			Order("next_attempt_at").
+			Limit(1).
+			Find(&deliveries).Error; err != nil {
+			return err
+		}
+
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		return tx.Model(&models.WebhookDelivery{}).
+			Where("id = ?", deliveries[0].ID).
+			Update("next_attempt_at", time.Now().Add(webhookLease)).Error
+	})
+	if err != nil || len(deliveries) == 0 {
+		return nil, err
+	}
+
+	return &deliveries[0], nil
+}
+
+func (d *WebhookDispatcher) deliver(delivery *models.WebhookDelivery) {
+	delivery.Attempts++
+
+	started := time.Now()
+	headers, statusCode, respBody, err := d.post(delivery)
+	d.recordAttempt(delivery, headers, statusCode, respBody, err, time.Since(started))
+
+	delivery.LastStatusCode = statusCode
+	delivery.LastResponse = respBody
+	delivery.LastError = ""
+	if err != nil {
+		delivery.LastError = err.Error()
+	}
+
+	now := time.Now()
+	horizon := time.Duration(d.cfg.WebhookDeliveryHorizon) * time.Hour
+
+	switch {
+	case err == nil && statusCode >= 200 && statusCode < 300:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		log.Printf("✅ [Webhook Externo] %s entregue para %s (tentativa %d)", delivery.Event, delivery.URL, delivery.Attempts)
+
+	case now.Sub(delivery.CreatedAt) >= horizon:
+		delivery.Status = models.WebhookDeliveryDead
+		log.Printf("💀 [Webhook Externo] %s para %s desistido após %d tentativas (HTTP %d, %s)", delivery.Event, delivery.URL, delivery.Attempts, statusCode, delivery.LastError)
+
+	default:
+		backoff := webhookBackoff(delivery.Attempts)
+		delivery.NextAttemptAt = now.Add(backoff)
+		log.Printf("⚠️ [Webhook Externo] Tentativa %d de %s para %s falhou (HTTP %d, %s); próxima em %s", delivery.Attempts, delivery.Event, delivery.URL, statusCode, delivery.LastError, backoff.Round(time.Second))
+	}
+
+	if err := d.db.Save(delivery).Error; err != nil {
+		log.Printf("❌ [Webhook Externo] Erro ao atualizar entrega %s: %v", delivery.ID, err)
+		d.releaseLease(delivery)
+	}
+}
+
+// releaseLease grava só o resultado da tentativa quando o Save completo falha, para a
+// entrega não ficar reservada até webhookLease vencer (nem ser reenviada depois de entregue)
+func (d *WebhookDispatcher) releaseLease(delivery *models.WebhookDelivery) {
+	if err := d.db.Model(&models.WebhookDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]interface{}{
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"delivered_at":    delivery.DeliveredAt,
+		}).Error; err != nil {
+		log.Printf("❌ [Webhook Externo] Erro ao liberar entrega %s: %v", delivery.ID, err)
+	}
+}
+
+func (d *WebhookDispatcher) post(delivery *models.WebhookDelivery) (http.Header, int, string, error) {
+	httpReq, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewBufferString(delivery.Payload))
+	if err != nil {
+		return nil, 0, "", err
+	}
+
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Server-APIs-Webhook/1.0")
+	httpReq.Header.Set("X-Webhook-Event", delivery.Event)
+	httpReq.Header.Set("X-Webhook-Delivery-ID", delivery.ID.String())
+
+	// Assina com todos os segredos ativos (dois durante a rotação)
+	if secrets := d.signingSecrets(delivery.URL); len(secrets) > 0 {
+		timestamp := time.Now().Unix()
+		httpReq.Header.Set(webhooksig.TimestampHeader, strconv.FormatInt(timestamp, 10))
+		httpReq.Header.Set(webhooksig.SignatureHeader, webhooksig.Header(timestamp, []byte(delivery.Payload), secrets...))
+	}
+
+	// Mantém o header enviado antes da fila de entregas
+	var meta struct {
+		TransactionID string `json:"transaction_id"`
+	}
+	if json.Unmarshal([]byte(delivery.Payload), &meta) == nil {
+		httpReq.Header.Set("X-Transaction-ID", meta.TransactionID)
+	}
+
+	resp, err := d.client.Do(httpReq)
+	if err != nil {
+		return httpReq.Header, 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxBodyStore))
+	return httpReq.Header, resp.StatusCode, sanitizeResponseBody(body), nil
+}
+
+// sanitizeResponseBody deixa a resposta gravável em coluna text do Postgres: o corte em
+// webhookMaxBodyStore pode partir um caractere e o Postgres recusa UTF-8 inválido e \x00
+func sanitizeResponseBody(body []byte) string {
+	return strings.ReplaceAll(strings.ToValidUTF8(string(body), ""), "\x00", "")
+}
+
+// recordAttempt grava o POST no histórico exibido em GET /api/v1/payments/:id/webhooks
+func (d *WebhookDispatcher) recordAttempt(delivery *models.WebhookDelivery, headers http.Header, statusCode int, respBody string, err error, latency time.Duration) {
+	attempt := &models.WebhookDeliveryAttempt{
+		DeliveryID:   delivery.ID,
+		OrderID:      delivery.OrderID,
+		Attempt:      delivery.Attempts,
+		URL:          delivery.URL,
+		StatusCode:   statusCode,
+		ResponseBody: respBody,
+		LatencyMs:    latency.Milliseconds(),
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+
+	requestHeaders, _ := json.Marshal(headers)
+	attempt.RequestHeaders = string(requestHeaders)
+
+	if err := d.db.Create(attempt).Error; err != nil {
+		log.Printf("⚠️ [Webhook Externo] Erro ao gravar tentativa da entrega %s: %v", delivery.ID, err)
+	}
+}
+
+// signingSecrets busca o segredo do endpoint cadastrado; sem cadastro usa WEBHOOK_SIGNING_SECRET
+func (d *WebhookDispatcher) signingSecrets(url string) []string {
+	var endpoint models.WebhookEndpoint
+	if err := d.db.Where("url = ?", url).Take(&endpoint).Error; err == nil {
+		return endpoint.ActiveSecrets(time.Now())
+	}
+
+	if d.cfg.WebhookSigningSecret != "" {
+		return []string{d.cfg.WebhookSigningSecret}
+	}
+	return nil
+}
+
+// webhookBackoff dobra a espera a cada tentativa (até webhookMaxBackoff) com jitter de até 50%
+func webhookBackoff(attempts int) time.Duration {
+	backoff := webhookBaseBackoff << uint(min(attempts-1, 12))
+	if backoff > webhookMaxBackoff {
+		backoff = webhookMaxBackoff
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
@@ -0,0 +1,54 @@
+package workers
+
+import (
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWebhookBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		max      time.Duration
+	}{
+		{1, webhookBaseBackoff},
+		{2, 2 * webhookBaseBackoff},
+		{3, 4 * webhookBaseBackoff},
+		{8, webhookBaseBackoff << 7},
+		{10, webhookMaxBackoff}, // 10s << 9 passa de uma hora
+		{50, webhookMaxBackoff}, // shift limitado, sem overflow
+	}
+
+	for _, tt := range tests {
+		// O jitter é aleatório: várias rodadas para cobrir a faixa [max/2, max]
+		for range 100 {
+			got := webhookBackoff(tt.attempts)
+			if got < tt.max/2 || got > tt.max {
+				t.Fatalf("webhookBackoff(%d) = %s, esperado entre %s e %s", tt.attempts, got, tt.max/2, tt.max)
+			}
+		}
+	}
+}
+
+func TestSanitizeResponseBody(t *testing.T) {
+	// "ação" cortado no meio do "ç" pelo LimitReader
+	truncated := []byte("ação")[:2]
+
+	tests := []struct {
+		name string
+		body []byte
+		want string
+	}{
+		{"JSON comum", []byte(`{"ok":true}`), `{"ok":true}`},
+		{"byte nulo", []byte("ok\x00fim"), "okfim"},
+		{"UTF-8 partido no corte", truncated, "a"},
+		{"binário", []byte{0xff, 0xfe, 'x', 0x00}, "x"},
+	}
+
+	for _, tt := range tests {
+		got := sanitizeResponseBody(tt.body)
+		if got != tt.want || !utf8.ValidString(got) {
+			t.Errorf("%s: sanitizeResponseBody() = %q, esperado %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,132 @@
+// Package webhooksig assina e verifica os webhooks enviados pela server-apis.
+//
+// Cada POST leva os headers:
+//
+//	X-Timestamp: 1700000000
+//	X-Signature: sha256=<hex>[,sha256=<hex>]
+//
+// A assinatura é HMAC-SHA256(secret, timestamp + "." + body). Durante a rotação
+// de segredo o header traz uma assinatura para cada segredo ativo, então o
+// receptor aceita o webhook com o segredo novo ou com o antigo.
+//
+// Uso no receptor:
+//
+//	body, err := webhooksig.VerifyRequest(r, 5*time.Minute, os.Getenv("WEBHOOK_SECRET"))
+//	if err != nil {
+//		http.Error(w, "assinatura inválida", http.StatusUnauthorized)
+//		return
+//	}
+package webhooksig
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Signature"
+	TimestampHeader = "X-Timestamp"
+
+	// DefaultTolerance é a diferença máxima aceita entre X-Timestamp e o relógio local
+	DefaultTolerance = 5 * time.Minute
+
+	signaturePrefix = "sha256="
+)
+
+var (
+	ErrMissingSignature = errors.New("webhooksig: assinatura ausente")
+	ErrInvalidTimestamp = errors.New("webhooksig: timestamp inválido")
+	ErrTimestampExpired = errors.New("webhooksig: timestamp fora da tolerância")
+	ErrNoSecret         = errors.New("webhooksig: nenhum segredo informado")
+	ErrInvalidSignature = errors.New("webhooksig: assinatura não confere")
+)
+
+// Sign devolve o HMAC-SHA256 em hexadecimal de timestamp + "." + body
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Header monta o valor de X-Signature com uma assinatura por segredo ativo
+func Header(timestamp int64, body []byte, secrets ...string) string {
+	parts := make([]string, 0, len(secrets))
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		parts = append(parts, signaturePrefix+Sign(secret, timestamp, body))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Verify confere X-Signature e X-Timestamp contra qualquer um dos segredos
+func Verify(signatureHeader, timestampHeader string, body []byte, tolerance time.Duration, secrets ...string) error {
+	if signatureHeader == "" {
+		return ErrMissingSignature
+	}
+
+	timestamp, err := strconv.ParseInt(strings.TrimSpace(timestampHeader), 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+
+	if tolerance > 0 {
+		diff := time.Since(time.Unix(timestamp, 0))
+		if diff > tolerance || diff < -tolerance {
+			return ErrTimestampExpired
+		}
+	}
+
+	var received [][]byte
+	for _, part := range strings.Split(signatureHeader, ",") {
+		sig, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(part), signaturePrefix))
+		if err == nil {
+			received = append(received, sig)
+		}
+	}
+
+	checked := false
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		checked = true
+
+		expected, _ := hex.DecodeString(Sign(secret, timestamp, body))
+		for _, sig := range received {
+			if hmac.Equal(sig, expected) {
+				return nil
+			}
+		}
+	}
+
+	if !checked {
+		return ErrNoSecret
+	}
+	return ErrInvalidSignature
+}
+
+// VerifyRequest lê o body, verifica a assinatura e devolve o body (que continua legível em r.Body)
+func VerifyRequest(r *http.Request, tolerance time.Duration, secrets ...string) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	if err := Verify(r.Header.Get(SignatureHeader), r.Header.Get(TimestampHeader), body, tolerance, secrets...); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
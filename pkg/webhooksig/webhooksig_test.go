@@ -0,0 +1,84 @@
+package webhooksig
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"event":"payment.approved"}`)
+	now := time.Now().Unix()
+	old := time.Now().Add(-time.Hour).Unix()
+	ts := strconv.FormatInt(now, 10)
+
+	tests := []struct {
+		name      string
+		signature string
+		timestamp string
+		body      []byte
+		tolerance time.Duration
+		secrets   []string
+		want      error
+	}{
+		{"segredo atual", Header(now, body, "novo"), ts, body, DefaultTolerance, []string{"novo"}, nil},
+		{"rotação aceita o segredo antigo", Header(now, body, "antigo"), ts, body, DefaultTolerance, []string{"novo", "antigo"}, nil},
+		{"header com as duas assinaturas", Header(now, body, "novo", "antigo"), ts, body, DefaultTolerance, []string{"antigo"}, nil},
+		{"sem prefixo sha256=", Sign("novo", now, body), ts, body, DefaultTolerance, []string{"novo"}, nil},
+		{"sem tolerância aceita timestamp antigo", Header(old, body, "novo"), strconv.FormatInt(old, 10), body, 0, []string{"novo"}, nil},
+		{"assinatura ausente", "", ts, body, DefaultTolerance, []string{"novo"}, ErrMissingSignature},
+		{"timestamp inválido", Header(now, body, "novo"), "abc", body, DefaultTolerance, []string{"novo"}, ErrInvalidTimestamp},
+		{"timestamp fora da tolerância", Header(old, body, "novo"), strconv.FormatInt(old, 10), body, DefaultTolerance, []string{"novo"}, ErrTimestampExpired},
+		{"segredo errado", Header(now, body, "outro"), ts, body, DefaultTolerance, []string{"novo"}, ErrInvalidSignature},
+		{"body alterado", Header(now, body, "novo"), ts, []byte(`{"event":"payment.refunded"}`), DefaultTolerance, []string{"novo"}, ErrInvalidSignature},
+		{"timestamp trocado", Header(now, body, "novo"), strconv.FormatInt(now+1, 10), body, DefaultTolerance, []string{"novo"}, ErrInvalidSignature},
+		{"assinatura que não é hex", "sha256=zz", ts, body, DefaultTolerance, []string{"novo"}, ErrInvalidSignature},
+		{"nenhum segredo", Header(now, body, "novo"), ts, body, DefaultTolerance, []string{""}, ErrNoSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.signature, tt.timestamp, tt.body, tt.tolerance, tt.secrets...)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() = %v, esperado %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestHeaderSkipsEmptySecrets(t *testing.T) {
+	body := []byte("{}")
+
+	if got, want := Header(1700000000, body, "", "novo", ""), "sha256="+Sign("novo", 1700000000, body); got != want {
+		t.Errorf("Header() = %q, esperado %q", got, want)
+	}
+	if got := Header(1700000000, body); got != "" {
+		t.Errorf("Header() sem segredos = %q, esperado vazio", got)
+	}
+}
+
+func TestVerifyRequestKeepsBody(t *testing.T) {
+	body := []byte(`{"id":"123"}`)
+	now := time.Now().Unix()
+
+	req := httptest.NewRequest("POST", "/webhook", bytes.NewReader(body))
+	req.Header.Set(SignatureHeader, Header(now, body, "novo"))
+	req.Header.Set(TimestampHeader, strconv.FormatInt(now, 10))
+
+	got, err := VerifyRequest(req, DefaultTolerance, "novo")
+	if err != nil {
+		t.Fatalf("VerifyRequest() = %v", err)
+	}
+	if !bytes.Equal(got, body) {
+		t.Errorf("body devolvido = %s, esperado %s", got, body)
+	}
+
+	again, _ := io.ReadAll(req.Body)
+	if !bytes.Equal(again, body) {
+		t.Errorf("r.Body depois da verificação = %s, esperado %s", again, body)
+	}
+}